@@ -0,0 +1,126 @@
+package main
+
+import (
+	"math"
+)
+
+// BoundingBox - axis aligned box, used to skip shapes a ray can't possibly hit
+type BoundingBox struct {
+	Min Tuple
+	Max Tuple
+}
+
+// NewBoundingBox - empty box, it holds nothing until points are added
+func NewBoundingBox() BoundingBox {
+	return BoundingBox{
+		Min: NewPoint(math.Inf(1), math.Inf(1), math.Inf(1)),
+		Max: NewPoint(math.Inf(-1), math.Inf(-1), math.Inf(-1)),
+	}
+}
+
+// NewBoundingBoxFrom - box spanning min to max
+func NewBoundingBoxFrom(min, max Tuple) BoundingBox {
+	return BoundingBox{min, max}
+}
+
+// infiniteBoundingBox - box holding all of space, for planes and anything containing one
+func infiniteBoundingBox() BoundingBox {
+	return BoundingBox{
+		Min: NewPoint(math.Inf(-1), math.Inf(-1), math.Inf(-1)),
+		Max: NewPoint(math.Inf(1), math.Inf(1), math.Inf(1)),
+	}
+}
+
+// Empty - whether nothing has been added to the box
+func (b BoundingBox) Empty() bool {
+	return b.Min.X > b.Max.X || b.Min.Y > b.Max.Y || b.Min.Z > b.Max.Z
+}
+
+// Finite - whether the box has a size on every axis that isn't infinite
+func (b BoundingBox) Finite() bool {
+	for _, f := range []float64{b.Min.X, b.Min.Y, b.Min.Z, b.Max.X, b.Max.Y, b.Max.Z} {
+		if math.IsInf(f, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddPoint - smallest box holding both this box and p
+func (b BoundingBox) AddPoint(p Tuple) BoundingBox {
+	return BoundingBox{
+		Min: NewPoint(math.Min(b.Min.X, p.X), math.Min(b.Min.Y, p.Y), math.Min(b.Min.Z, p.Z)),
+		Max: NewPoint(math.Max(b.Max.X, p.X), math.Max(b.Max.Y, p.Y), math.Max(b.Max.Z, p.Z)),
+	}
+}
+
+// AddBox - smallest box holding both boxes
+func (b BoundingBox) AddBox(o BoundingBox) BoundingBox {
+	if o.Empty() {
+		return b
+	}
+	return b.AddPoint(o.Min).AddPoint(o.Max)
+}
+
+// ContainsPoint - whether p is inside the box or on its surface
+func (b BoundingBox) ContainsPoint(p Tuple) bool {
+	return b.Min.X <= p.X && p.X <= b.Max.X &&
+		b.Min.Y <= p.Y && p.Y <= b.Max.Y &&
+		b.Min.Z <= p.Z && p.Z <= b.Max.Z
+}
+
+// ContainsBox - whether o fits entirely inside the box
+func (b BoundingBox) ContainsBox(o BoundingBox) bool {
+	return b.ContainsPoint(o.Min) && b.ContainsPoint(o.Max)
+}
+
+// Transform - axis aligned box holding this box after it is transformed by m
+func (b BoundingBox) Transform(m Mat4) BoundingBox {
+	if b.Empty() {
+		return b
+	}
+	// infinity times the zeros in m gives NaN, so stay conservative
+	if !b.Finite() {
+		return infiniteBoundingBox()
+	}
+
+	corners := []Tuple{
+		b.Min,
+		NewPoint(b.Min.X, b.Min.Y, b.Max.Z),
+		NewPoint(b.Min.X, b.Max.Y, b.Min.Z),
+		NewPoint(b.Min.X, b.Max.Y, b.Max.Z),
+		NewPoint(b.Max.X, b.Min.Y, b.Min.Z),
+		NewPoint(b.Max.X, b.Min.Y, b.Max.Z),
+		NewPoint(b.Max.X, b.Max.Y, b.Min.Z),
+		b.Max,
+	}
+	result := NewBoundingBox()
+	for _, c := range corners {
+		result = result.AddPoint(m.MulT(c))
+	}
+	return result
+}
+
+// Intersects - whether the ray passes through the box, same slab test as Cube
+func (b BoundingBox) Intersects(r Ray) bool {
+	if b.Empty() {
+		return false
+	}
+	xtmin, xtmax := checkAxis(r.Origin.X, r.Direction.X, b.Min.X, b.Max.X)
+	ytmin, ytmax := checkAxis(r.Origin.Y, r.Direction.Y, b.Min.Y, b.Max.Y)
+	ztmin, ztmax := checkAxis(r.Origin.Z, r.Direction.Z, b.Min.Z, b.Max.Z)
+
+	tmin := math.Max(xtmin, math.Max(ytmin, ztmin))
+	tmax := math.Min(xtmax, math.Min(ytmax, ztmax))
+	return tmin <= tmax
+}
+
+// Centroid - point in the middle of the box
+func (b BoundingBox) Centroid() Tuple {
+	return NewPoint((b.Min.X+b.Max.X)/2, (b.Min.Y+b.Max.Y)/2, (b.Min.Z+b.Max.Z)/2)
+}
+
+// ParentSpaceBounds - bounds of a shape in the space of the group that holds it
+func ParentSpaceBounds(s Shape) BoundingBox {
+	return s.Bounds().Transform(s.Transform())
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+	Scenario: Creating an empty bounding box
+	Given box ← bounding_box(empty)
+	Then box.min = point(infinity, infinity, infinity)
+	And box.max = point(-infinity, -infinity, -infinity)
+*/
+func TestCreateEmptyBoundingBox(t *testing.T) {
+	box := NewBoundingBox()
+	assert.Equal(t, NewPoint(math.Inf(1), math.Inf(1), math.Inf(1)), box.Min)
+	assert.Equal(t, NewPoint(math.Inf(-1), math.Inf(-1), math.Inf(-1)), box.Max)
+	assert.True(t, box.Empty())
+}
+
+/*
+	Scenario: Creating a bounding box with volume
+	Given box ← bounding_box(min=point(-1, -2, -3) max=point(3, 2, 1))
+	Then box.min = point(-1, -2, -3)
+	And box.max = point(3, 2, 1)
+*/
+func TestCreateBoundingBoxWithVolume(t *testing.T) {
+	box := NewBoundingBoxFrom(NewPoint(-1, -2, -3), NewPoint(3, 2, 1))
+	assert.Equal(t, NewPoint(-1, -2, -3), box.Min)
+	assert.Equal(t, NewPoint(3, 2, 1), box.Max)
+	assert.False(t, box.Empty())
+}
+
+/*
+	Scenario: Adding points to an empty bounding box
+	Given box ← bounding_box(empty)
+	And p1 ← point(-5, 2, 0)
+	And p2 ← point(7, 0, -3)
+	When p1 is added to box
+	And p2 is added to box
+	Then box.min = point(-5, 0, -3)
+	And box.max = point(7, 2, 0)
+*/
+func TestAddPointsToBoundingBox(t *testing.T) {
+	box := NewBoundingBox().AddPoint(NewPoint(-5, 2, 0)).AddPoint(NewPoint(7, 0, -3))
+	assert.Equal(t, NewPoint(-5, 0, -3), box.Min)
+	assert.Equal(t, NewPoint(7, 2, 0), box.Max)
+}
+
+/*
+	Scenario Outline: Each primitive has a bounding box
+	Given shape ← <shape>
+	When box ← bounds_of(shape)
+	Then box.min = <min>
+	And box.max = <max>
+
+	Examples:
+		| shape                                   | min                           | max                        |
+		| sphere()                                | point(-1, -1, -1)             | point(1, 1, 1)             |
+		| plane()                                 | point(-infinity, 0, -infinity) | point(infinity, 0, infinity) |
+		| cube()                                  | point(-1, -1, -1)             | point(1, 1, 1)             |
+		| cylinder()                              | point(-1, -infinity, -1)      | point(1, infinity, 1)      |
+		| cylinder() with min -5, max 3           | point(-1, -5, -1)             | point(1, 3, 1)             |
+		| cone()                                  | point(-infinity, -infinity, -infinity) | point(infinity, infinity, infinity) |
+		| cone() with min -5, max 3               | point(-5, -5, -5)             | point(5, 3, 5)             |
+		| triangle(p(-3,7,2), p(6,2,-4), p(2,-1,-1)) | point(-3, -1, -4)          | point(6, 7, 2)             |
+*/
+func TestPrimitiveBounds(t *testing.T) {
+	inf := math.Inf(1)
+
+	cyl := NewCylinder()
+	cyl.SetMinimum(-5)
+	cyl.SetMaximum(3)
+	cone := NewCone()
+	cone.SetMinimum(-5)
+	cone.SetMaximum(3)
+
+	examples := []struct {
+		shape Shape
+		min   Tuple
+		max   Tuple
+	}{
+		{NewSphere(), NewPoint(-1, -1, -1), NewPoint(1, 1, 1)},
+		{NewPlane(), NewPoint(-inf, 0, -inf), NewPoint(inf, 0, inf)},
+		{NewCube(), NewPoint(-1, -1, -1), NewPoint(1, 1, 1)},
+		{NewCylinder(), NewPoint(-1, -inf, -1), NewPoint(1, inf, 1)},
+		{cyl, NewPoint(-1, -5, -1), NewPoint(1, 3, 1)},
+		{NewCone(), NewPoint(-inf, -inf, -inf), NewPoint(inf, inf, inf)},
+		{cone, NewPoint(-5, -5, -5), NewPoint(5, 3, 5)},
+		{NewTriangle(NewPoint(-3, 7, 2), NewPoint(6, 2, -4), NewPoint(2, -1, -1)), NewPoint(-3, -1, -4), NewPoint(6, 7, 2)},
+	}
+	for _, e := range examples {
+		box := e.shape.Bounds()
+		assert.Equal(t, e.min, box.Min)
+		assert.Equal(t, e.max, box.Max)
+	}
+}
+
+/*
+	Scenario: Adding one bounding box to another
+	Given box1 ← bounding_box(min=point(-5, -2, 0) max=point(7, 4, 4))
+	And box2 ← bounding_box(min=point(8, -7, -2) max=point(14, 2, 8))
+	When box2 is added to box1
+	Then box1.min = point(-5, -7, -2)
+	And box1.max = point(14, 4, 8)
+*/
+func TestAddBoundingBoxes(t *testing.T) {
+	box1 := NewBoundingBoxFrom(NewPoint(-5, -2, 0), NewPoint(7, 4, 4))
+	box2 := NewBoundingBoxFrom(NewPoint(8, -7, -2), NewPoint(14, 2, 8))
+	box1 = box1.AddBox(box2)
+	assert.Equal(t, NewPoint(-5, -7, -2), box1.Min)
+	assert.Equal(t, NewPoint(14, 4, 8), box1.Max)
+}
+
+/*
+	Scenario Outline: Checking to see if a box contains a given point
+	Given box ← bounding_box(min=point(5, -2, 0) max=point(11, 4, 7))
+	And p ← <point>
+	Then box_contains_point(box, p) is <result>
+
+	Examples:
+		| point           | result |
+		| point(5, -2, 0) | true   |
+		| point(11, 4, 7) | true   |
+		| point(8, 1, 3)  | true   |
+		| point(3, 0, 3)  | false  |
+		| point(8, -4, 3) | false  |
+		| point(8, 1, -1) | false  |
+		| point(13, 1, 3) | false  |
+		| point(8, 5, 3)  | false  |
+		| point(8, 1, 8)  | false  |
+*/
+func TestBoundingBoxContainsPoint(t *testing.T) {
+	box := NewBoundingBoxFrom(NewPoint(5, -2, 0), NewPoint(11, 4, 7))
+	examples := []struct {
+		point  Tuple
+		result bool
+	}{
+		{NewPoint(5, -2, 0), true},
+		{NewPoint(11, 4, 7), true},
+		{NewPoint(8, 1, 3), true},
+		{NewPoint(3, 0, 3), false},
+		{NewPoint(8, -4, 3), false},
+		{NewPoint(8, 1, -1), false},
+		{NewPoint(13, 1, 3), false},
+		{NewPoint(8, 5, 3), false},
+		{NewPoint(8, 1, 8), false},
+	}
+	for _, e := range examples {
+		assert.Equal(t, e.result, box.ContainsPoint(e.point), "%v", e.point)
+	}
+}
+
+/*
+	Scenario Outline: Checking to see if a box contains a given box
+	Given box ← bounding_box(min=point(5, -2, 0) max=point(11, 4, 7))
+	And box2 ← bounding_box(min=<min> max=<max>)
+	Then box_contains_box(box, box2) is <result>
+
+	Examples:
+		| min              | max             | result |
+		| point(5, -2, 0)  | point(11, 4, 7) | true   |
+		| point(6, -1, 1)  | point(10, 3, 6) | true   |
+		| point(4, -3, -1) | point(10, 3, 6) | false  |
+		| point(6, -1, 1)  | point(12, 5, 8) | false  |
+*/
+func TestBoundingBoxContainsBox(t *testing.T) {
+	box := NewBoundingBoxFrom(NewPoint(5, -2, 0), NewPoint(11, 4, 7))
+	examples := []struct {
+		min    Tuple
+		max    Tuple
+		result bool
+	}{
+		{NewPoint(5, -2, 0), NewPoint(11, 4, 7), true},
+		{NewPoint(6, -1, 1), NewPoint(10, 3, 6), true},
+		{NewPoint(4, -3, -1), NewPoint(10, 3, 6), false},
+		{NewPoint(6, -1, 1), NewPoint(12, 5, 8), false},
+	}
+	for _, e := range examples {
+		assert.Equal(t, e.result, box.ContainsBox(NewBoundingBoxFrom(e.min, e.max)))
+	}
+}
+
+/*
+	Scenario: Transforming a bounding box
+	Given box ← bounding_box(min=point(-1, -1, -1) max=point(1, 1, 1))
+	And matrix ← rotation_x(π / 4) * rotation_y(π / 4)
+	When box2 ← transform(box, matrix)
+	Then box2.min = point(-1.4142, -1.7071, -1.7071)
+	And box2.max = point(1.4142, 1.7071, 1.7071)
+*/
+func TestTransformBoundingBox(t *testing.T) {
+	box := NewBoundingBoxFrom(NewPoint(-1, -1, -1), NewPoint(1, 1, 1))
+	m := NewRotationY(math.Pi / 4).RotateX(math.Pi / 4)
+	box2 := box.Transform(m)
+	assert.InDeltaSlice(t, []float64{-1.4142, -1.7071, -1.7071}, []float64{box2.Min.X, box2.Min.Y, box2.Min.Z}, 0.0001)
+	assert.InDeltaSlice(t, []float64{1.4142, 1.7071, 1.7071}, []float64{box2.Max.X, box2.Max.Y, box2.Max.Z}, 0.0001)
+}
+
+/*
+	Scenario: Transforming an infinite bounding box stays infinite
+	Given box ← bounds_of(plane())
+	When box2 ← transform(box, rotation_x(π / 4))
+	Then box2 holds all of space
+*/
+func TestTransformInfiniteBoundingBox(t *testing.T) {
+	box := NewPlane().Bounds().Transform(NewRotationX(math.Pi / 4))
+	assert.Equal(t, infiniteBoundingBox(), box)
+}
+
+/*
+	Scenario: Querying a shape's bounding box in its parent's space
+	Given shape ← sphere()
+	And set_transform(shape, translation(1, -3, 5) * scaling(0.5, 2, 4))
+	When box ← parent_space_bounds_of(shape)
+	Then box.min = point(0.5, -5, 1)
+	And box.max = point(1.5, -1, 9)
+*/
+func TestParentSpaceBounds(t *testing.T) {
+	shape := NewSphere().WithTransform(NewScaling(0.5, 2, 4).Translate(1, -3, 5))
+	box := ParentSpaceBounds(shape)
+	assert.True(t, box.Min.Equal(NewPoint(0.5, -5, 1)))
+	assert.True(t, box.Max.Equal(NewPoint(1.5, -1, 9)))
+}
+
+/*
+	Scenario: A group has a bounding box that contains its children
+	Given s ← sphere()
+	And set_transform(s, translation(2, 5, -3) * scaling(2, 2, 2))
+	And c ← cylinder()
+	And c.minimum ← -2
+	And c.maximum ← 2
+	And set_transform(c, translation(-4, -1, 4) * scaling(0.5, 1, 0.5))
+	And shape ← group()
+	And add_child(shape, s)
+	And add_child(shape, c)
+	When box ← bounds_of(shape)
+	Then box.min = point(-4.5, -3, -5)
+	And box.max = point(4, 7, 4.5)
+*/
+func TestGroupBounds(t *testing.T) {
+	s := NewSphere().WithTransform(NewScaling(2, 2, 2).Translate(2, 5, -3))
+	c := NewCylinder()
+	c.SetMinimum(-2)
+	c.SetMaximum(2)
+	c.SetTransform(NewScaling(0.5, 1, 0.5).Translate(-4, -1, 4))
+	shape := NewGroup()
+	shape.AddChild(s, c)
+	box := shape.Bounds()
+	assert.True(t, box.Min.Equal(NewPoint(-4.5, -3, -5)))
+	assert.True(t, box.Max.Equal(NewPoint(4, 7, 4.5)))
+}
+
+/*
+	Scenario: A group's bounding box follows its children's transforms
+	Given s ← sphere()
+	And shape ← group()
+	And add_child(shape, s)
+	When set_transform(s, translation(10, 0, 0))
+	Then bounds_of(shape).min = point(9, -1, -1)
+	And bounds_of(shape).max = point(11, 1, 1)
+*/
+func TestGroupBoundsFollowChildTransform(t *testing.T) {
+	s := NewSphere()
+	shape := NewGroup()
+	shape.AddChild(s)
+	s.SetTransform(NewTranslation(10, 0, 0))
+	box := shape.Bounds()
+	assert.True(t, box.Min.Equal(NewPoint(9, -1, -1)))
+	assert.True(t, box.Max.Equal(NewPoint(11, 1, 1)))
+}
+
+/*
+	Scenario: A CSG shape has a bounding box that contains its children
+	Given left ← sphere()
+	And right ← sphere()
+	And set_transform(right, translation(2, 3, 4))
+	And shape ← csg("difference", left, right)
+	When box ← bounds_of(shape)
+	Then box.min = point(-1, -1, -1)
+	And box.max = point(3, 4, 5)
+*/
+func TestCSGBounds(t *testing.T) {
+	left := NewSphere()
+	right := NewSphere().WithTransform(NewTranslation(2, 3, 4))
+	shape := NewCSG(CSGDifference, left, right)
+	box := shape.Bounds()
+	assert.True(t, box.Min.Equal(NewPoint(-1, -1, -1)))
+	assert.True(t, box.Max.Equal(NewPoint(3, 4, 5)))
+}
+
+/*
+	Scenario Outline: Intersecting a ray with a bounding box at the origin
+	Given box ← bounding_box(min=point(-1, -1, -1) max=point(1, 1, 1))
+	And direction ← normalize(<direction>)
+	And r ← ray(<origin>, direction)
+	Then intersects(box, r) is <result>
+
+	Examples:
+		| origin            | direction        | result |
+		| point(5, 0.5, 0)  | vector(-1, 0, 0) | true   |
+		| point(-5, 0.5, 0) | vector(1, 0, 0)  | true   |
+		| point(0.5, 5, 0)  | vector(0, -1, 0) | true   |
+		| point(0.5, -5, 0) | vector(0, 1, 0)  | true   |
+		| point(0.5, 0, 5)  | vector(0, 0, -1) | true   |
+		| point(0.5, 0, -5) | vector(0, 0, 1)  | true   |
+		| point(0, 0.5, 0)  | vector(0, 0, 1)  | true   |
+		| point(-2, 0, 0)   | vector(2, 4, 6)  | false  |
+		| point(0, -2, 0)   | vector(6, 2, 4)  | false  |
+		| point(0, 0, -2)   | vector(4, 6, 2)  | false  |
+		| point(2, 0, 2)    | vector(0, 0, -1) | false  |
+		| point(0, 2, 2)    | vector(0, -1, 0) | false  |
+		| point(2, 2, 0)    | vector(-1, 0, 0) | false  |
+*/
+func TestRayIntersectsCubicBoundingBox(t *testing.T) {
+	box := NewBoundingBoxFrom(NewPoint(-1, -1, -1), NewPoint(1, 1, 1))
+	examples := []struct {
+		origin    Tuple
+		direction Tuple
+		result    bool
+	}{
+		{NewPoint(5, 0.5, 0), NewVector(-1, 0, 0), true},
+		{NewPoint(-5, 0.5, 0), NewVector(1, 0, 0), true},
+		{NewPoint(0.5, 5, 0), NewVector(0, -1, 0), true},
+		{NewPoint(0.5, -5, 0), NewVector(0, 1, 0), true},
+		{NewPoint(0.5, 0, 5), NewVector(0, 0, -1), true},
+		{NewPoint(0.5, 0, -5), NewVector(0, 0, 1), true},
+		{NewPoint(0, 0.5, 0), NewVector(0, 0, 1), true},
+		{NewPoint(-2, 0, 0), NewVector(2, 4, 6), false},
+		{NewPoint(0, -2, 0), NewVector(6, 2, 4), false},
+		{NewPoint(0, 0, -2), NewVector(4, 6, 2), false},
+		{NewPoint(2, 0, 2), NewVector(0, 0, -1), false},
+		{NewPoint(0, 2, 2), NewVector(0, -1, 0), false},
+		{NewPoint(2, 2, 0), NewVector(-1, 0, 0), false},
+	}
+	for _, e := range examples {
+		r := Ray{e.origin, e.direction.Norm()}
+		assert.Equal(t, e.result, box.Intersects(r), "%v %v", e.origin, e.direction)
+	}
+}
+
+/*
+	Scenario Outline: Intersecting a ray with a non-cubic bounding box
+	Given box ← bounding_box(min=point(5, -2, 0) max=point(11, 4, 7))
+	And direction ← normalize(<direction>)
+	And r ← ray(<origin>, direction)
+	Then intersects(box, r) is <result>
+
+	Examples:
+		| origin           | direction        | result |
+		| point(15, 1, 2)  | vector(-1, 0, 0) | true   |
+		| point(-5, -1, 4) | vector(1, 0, 0)  | true   |
+		| point(7, 6, 5)   | vector(0, -1, 0) | true   |
+		| point(9, -5, 6)  | vector(0, 1, 0)  | true   |
+		| point(8, 2, 12)  | vector(0, 0, -1) | true   |
+		| point(6, 0, -5)  | vector(0, 0, 1)  | true   |
+		| point(8, 1, 3.5) | vector(0, 0, 1)  | true   |
+		| point(5, 1, -5)  | vector(0, 0, 1)  | true   |
+		| point(9, -1, -8) | vector(2, 4, 6)  | false  |
+		| point(8, 3, -4)  | vector(6, 2, 4)  | false  |
+		| point(9, -1, -2) | vector(4, 6, 2)  | false  |
+		| point(4, 0, 9)   | vector(0, 0, -1) | false  |
+		| point(8, 6, -1)  | vector(0, -1, 0) | false  |
+		| point(12, 5, 4)  | vector(-1, 0, 0) | false  |
+*/
+func TestRayIntersectsNonCubicBoundingBox(t *testing.T) {
+	box := NewBoundingBoxFrom(NewPoint(5, -2, 0), NewPoint(11, 4, 7))
+	examples := []struct {
+		origin    Tuple
+		direction Tuple
+		result    bool
+	}{
+		{NewPoint(15, 1, 2), NewVector(-1, 0, 0), true},
+		{NewPoint(-5, -1, 4), NewVector(1, 0, 0), true},
+		{NewPoint(7, 6, 5), NewVector(0, -1, 0), true},
+		{NewPoint(9, -5, 6), NewVector(0, 1, 0), true},
+		{NewPoint(8, 2, 12), NewVector(0, 0, -1), true},
+		{NewPoint(6, 0, -5), NewVector(0, 0, 1), true},
+		{NewPoint(8, 1, 3.5), NewVector(0, 0, 1), true},
+		{NewPoint(5, 1, -5), NewVector(0, 0, 1), true},
+		{NewPoint(9, -1, -8), NewVector(2, 4, 6), false},
+		{NewPoint(8, 3, -4), NewVector(6, 2, 4), false},
+		{NewPoint(9, -1, -2), NewVector(4, 6, 2), false},
+		{NewPoint(4, 0, 9), NewVector(0, 0, -1), false},
+		{NewPoint(8, 6, -1), NewVector(0, -1, 0), false},
+		{NewPoint(12, 5, 4), NewVector(-1, 0, 0), false},
+	}
+	for _, e := range examples {
+		r := Ray{e.origin, e.direction.Norm()}
+		assert.Equal(t, e.result, box.Intersects(r), "%v %v", e.origin, e.direction)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"math"
+)
+
+// Camera - maps the canvas one unit in front of the eye onto the world
+type Camera struct {
+	HSize       int
+	VSize       int
+	FieldOfView float64
+
+	transform  Mat4
+	inverse    Mat4
+	halfWidth  float64
+	halfHeight float64
+	pixelSize  float64
+}
+
+// NewCamera - camera of the given canvas size and field of view, at the origin looking down -z
+func NewCamera(hsize, vsize int, fieldOfView float64) Camera {
+	c := Camera{
+		HSize:       hsize,
+		VSize:       vsize,
+		FieldOfView: fieldOfView,
+		transform:   NewIdentityMat4(),
+		inverse:     NewIdentityMat4(),
+	}
+
+	halfView := math.Tan(fieldOfView / 2)
+	aspect := float64(hsize) / float64(vsize)
+	if aspect >= 1 {
+		c.halfWidth = halfView
+		c.halfHeight = halfView / aspect
+	} else {
+		c.halfWidth = halfView * aspect
+		c.halfHeight = halfView
+	}
+	c.pixelSize = (c.halfWidth * 2) / float64(hsize)
+
+	return c
+}
+
+// Transform - view transform of the camera
+func (c Camera) Transform() Mat4 {
+	return c.transform
+}
+
+// SetTransform - set the view transform, panics if it cannot be inverted
+func (c *Camera) SetTransform(m Mat4) {
+	c.transform = m
+	c.inverse = m.MustInverse()
+}
+
+// WithTransform - fluent version of SetTransform
+func (c Camera) WithTransform(m Mat4) Camera {
+	c.SetTransform(m)
+	return c
+}
+
+// PixelSize - size of a single pixel on the canvas, in world units
+func (c Camera) PixelSize() float64 {
+	return c.pixelSize
+}
+
+// RayForPixel - ray from the camera through the center of the given pixel
+func (c Camera) RayForPixel(x, y int) Ray {
+	// offset from the edge of the canvas to the pixel's center
+	xOffset := (float64(x) + 0.5) * c.pixelSize
+	yOffset := (float64(y) + 0.5) * c.pixelSize
+
+	// untransformed coordinates of the pixel in world space.
+	// the camera looks toward -z, so +x is to the *left*
+	worldX := c.halfWidth - xOffset
+	worldY := c.halfHeight - yOffset
+
+	// canvas is at z = -1
+	pixel := c.inverse.MulT(NewPoint(worldX, worldY, -1))
+	origin := c.inverse.MulT(NewPoint(0, 0, 0))
+	direction := pixel.Sub(origin).Norm()
+
+	return Ray{origin, direction}
+}
+
+// Render - render the world to a canvas, one ray per pixel
+func (c Camera) Render(w World) Canvas {
+	image := NewCanvas(c.HSize, c.VSize)
+	for y := 0; y < c.VSize; y++ {
+		for x := 0; x < c.HSize; x++ {
+			r := c.RayForPixel(x, y)
+			image.WritePixel(x, y, w.ColorAt(r))
+		}
+	}
+	return image
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+	Scenario: Constructing a camera
+	Given hsize ← 160
+	And vsize ← 120
+	And field_of_view ← π/2
+	When c ← camera(hsize, vsize, field_of_view)
+	Then c.hsize = 160
+	And c.vsize = 120
+	And c.field_of_view = π/2
+	And c.transform = identity_matrix
+*/
+func TestCreateCamera(t *testing.T) {
+	c := NewCamera(160, 120, math.Pi/2)
+	assert.Equal(t, 160, c.HSize)
+	assert.Equal(t, 120, c.VSize)
+	assert.Equal(t, math.Pi/2, c.FieldOfView)
+	assert.Equal(t, NewIdentityMat4(), c.Transform())
+}
+
+/*
+	Scenario: The pixel size for a horizontal canvas
+	Given c ← camera(200, 125, π/2)
+	Then c.pixel_size = 0.01
+*/
+func TestPixelSizeHorizontalCanvas(t *testing.T) {
+	c := NewCamera(200, 125, math.Pi/2)
+	assert.InDelta(t, 0.01, c.PixelSize(), epsilon)
+}
+
+/*
+	Scenario: The pixel size for a vertical canvas
+	Given c ← camera(125, 200, π/2)
+	Then c.pixel_size = 0.01
+*/
+func TestPixelSizeVerticalCanvas(t *testing.T) {
+	c := NewCamera(125, 200, math.Pi/2)
+	assert.InDelta(t, 0.01, c.PixelSize(), epsilon)
+}
+
+/*
+	Scenario: Constructing a ray through the center of the canvas
+	Given c ← camera(201, 101, π/2)
+	When r ← ray_for_pixel(c, 100, 50)
+	Then r.origin = point(0, 0, 0)
+	And r.direction = vector(0, 0, -1)
+*/
+func TestRayForPixelCenter(t *testing.T) {
+	c := NewCamera(201, 101, math.Pi/2)
+	r := c.RayForPixel(100, 50)
+	assert.True(t, r.Origin.Equal(NewPoint(0, 0, 0)))
+	assert.True(t, r.Direction.Equal(NewVector(0, 0, -1)))
+}
+
+/*
+	Scenario: Constructing a ray through a corner of the canvas
+	Given c ← camera(201, 101, π/2)
+	When r ← ray_for_pixel(c, 0, 0)
+	Then r.origin = point(0, 0, 0)
+	And r.direction = vector(0.66519, 0.33259, -0.66851)
+*/
+func TestRayForPixelCorner(t *testing.T) {
+	c := NewCamera(201, 101, math.Pi/2)
+	r := c.RayForPixel(0, 0)
+	assert.True(t, r.Origin.Equal(NewPoint(0, 0, 0)))
+	assert.True(t, r.Direction.Equal(NewVector(0.66519, 0.33259, -0.66851)))
+}
+
+/*
+	Scenario: Constructing a ray when the camera is transformed
+	Given c ← camera(201, 101, π/2)
+	When c.transform ← rotation_y(π/4) * translation(0, -2, 5)
+	And r ← ray_for_pixel(c, 100, 50)
+	Then r.origin = point(0, 2, -5)
+	And r.direction = vector(√2/2, 0, -√2/2)
+*/
+func TestRayForPixelTransformedCamera(t *testing.T) {
+	c := NewCamera(201, 101, math.Pi/2)
+	c.SetTransform(NewTranslation(0, -2, 5).RotateY(math.Pi / 4))
+	r := c.RayForPixel(100, 50)
+	assert.True(t, r.Origin.Equal(NewPoint(0, 2, -5)))
+	assert.True(t, r.Direction.Equal(NewVector(math.Sqrt2/2, 0, -math.Sqrt2/2)))
+}
+
+/*
+	Scenario: Rendering a world with a camera
+	Given w ← default_world()
+	And c ← camera(11, 11, π/2)
+	And from ← point(0, 0, -5)
+	And to ← point(0, 0, 0)
+	And up ← vector(0, 1, 0)
+	And c.transform ← view_transform(from, to, up)
+	When image ← render(c, w)
+	Then pixel_at(image, 5, 5) = color(0.38066, 0.47583, 0.2855)
+*/
+func TestRenderWorldWithCamera(t *testing.T) {
+	w := defaultWorld()
+	from := NewPoint(0, 0, -5)
+	to := NewPoint(0, 0, 0)
+	up := NewVector(0, 1, 0)
+	c := NewCamera(11, 11, math.Pi/2).WithTransform(ViewTransform(from, to, up))
+	image := c.Render(w)
+	assert.True(t, image.PixelAt(5, 5).Equal(Color{0.38066, 0.47583, 0.2855}))
+}
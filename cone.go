@@ -0,0 +1,146 @@
+package main
+
+import (
+	"math"
+)
+
+// Cone - double napped cone around the y axis with its apex at the origin,
+// optionally truncated and capped. Radius at any y is |y|
+type Cone struct {
+	shape
+	// minimum and maximum - y values the cone is truncated at, as with Cylinder
+	// only changed through the setters so any group holding it is told
+	minimum float64
+	maximum float64
+	Closed  bool
+}
+
+// NewCone - infinitely long, open cone
+func NewCone() *Cone {
+	return &Cone{
+		shape:   newShape(),
+		minimum: math.Inf(-1),
+		maximum: math.Inf(1),
+	}
+}
+
+func (c *Cone) WithTransform(t Mat4) *Cone {
+	c.SetTransform(t)
+	return c
+}
+
+func (c *Cone) WithMaterial(m Material) *Cone {
+	c.SetMaterial(m)
+	return c
+}
+
+func (c *Cone) Minimum() float64 {
+	return c.minimum
+}
+
+func (c *Cone) Maximum() float64 {
+	return c.maximum
+}
+
+// SetMinimum - truncate the cone below y, updating the bounds of its parent
+func (c *Cone) SetMinimum(y float64) {
+	c.minimum = y
+	if c.parent != nil {
+		c.parent.boundsChanged()
+	}
+}
+
+// SetMaximum - truncate the cone above y, updating the bounds of its parent
+func (c *Cone) SetMaximum(y float64) {
+	c.maximum = y
+	if c.parent != nil {
+		c.parent.boundsChanged()
+	}
+}
+
+func (c *Cone) LocalIntersect(r Ray) Intersections {
+	xs := Intersections{}
+
+	a := square(r.Direction.X) - square(r.Direction.Y) + square(r.Direction.Z)
+	b := 2*r.Origin.X*r.Direction.X - 2*r.Origin.Y*r.Direction.Y + 2*r.Origin.Z*r.Direction.Z
+	c2 := square(r.Origin.X) - square(r.Origin.Y) + square(r.Origin.Z)
+
+	if math.Abs(a) < epsilon {
+		// parallel to one of the halves, at most one hit on the other half
+		if math.Abs(b) >= epsilon {
+			t := -c2 / (2 * b)
+			y := r.Origin.Y + t*r.Direction.Y
+			if c.minimum < y && y < c.maximum {
+				xs = append(xs, NewIntersection(t, c))
+			}
+		}
+		return c.intersectCaps(r, xs)
+	}
+
+	disc := b*b - 4*a*c2
+	if disc < 0 {
+		return c.intersectCaps(r, xs)
+	}
+
+	t0 := (-b - math.Sqrt(disc)) / (2 * a)
+	t1 := (-b + math.Sqrt(disc)) / (2 * a)
+	if t0 > t1 {
+		t0, t1 = t1, t0
+	}
+
+	y0 := r.Origin.Y + t0*r.Direction.Y
+	if c.minimum < y0 && y0 < c.maximum {
+		xs = append(xs, NewIntersection(t0, c))
+	}
+	y1 := r.Origin.Y + t1*r.Direction.Y
+	if c.minimum < y1 && y1 < c.maximum {
+		xs = append(xs, NewIntersection(t1, c))
+	}
+
+	return c.intersectCaps(r, xs)
+}
+
+func (c *Cone) intersectCaps(r Ray, xs Intersections) Intersections {
+	if !c.Closed || math.Abs(r.Direction.Y) < epsilon {
+		return xs
+	}
+
+	// the caps are as wide as the cone is at that height
+	// an end left infinite has no cap
+	if !math.IsInf(c.minimum, 0) {
+		t := (c.minimum - r.Origin.Y) / r.Direction.Y
+		if checkCap(r, t, math.Abs(c.minimum)) {
+			xs = append(xs, NewIntersection(t, c))
+		}
+	}
+	if !math.IsInf(c.maximum, 0) {
+		t := (c.maximum - r.Origin.Y) / r.Direction.Y
+		if checkCap(r, t, math.Abs(c.maximum)) {
+			xs = append(xs, NewIntersection(t, c))
+		}
+	}
+	return xs
+}
+
+func (c *Cone) LocalNormalAt(p Tuple, _ Intersection) Tuple {
+	dist := square(p.X) + square(p.Z)
+
+	if dist < square(c.maximum) && p.Y >= c.maximum-epsilon {
+		return NewVector(0, 1, 0)
+	}
+	if dist < square(c.minimum) && p.Y <= c.minimum+epsilon {
+		return NewVector(0, -1, 0)
+	}
+
+	y := math.Sqrt(dist)
+	if p.Y > 0 {
+		y = -y
+	}
+	return NewVector(p.X, y, p.Z)
+}
+
+func (c *Cone) Bounds() BoundingBox {
+	// widest at whichever end is furthest from the apex
+	r := math.Max(math.Abs(c.minimum), math.Abs(c.maximum))
+	return NewBoundingBoxFrom(NewPoint(-r, c.minimum, -r), NewPoint(r, c.maximum, r))
+}
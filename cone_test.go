@@ -0,0 +1,177 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario Outline: Intersecting a cone with a ray
+	Given shape ← cone()
+	And direction ← normalize(<direction>)
+	And r ← ray(<origin>, direction)
+	When xs ← local_intersect(shape, r)
+	Then xs.count = 2
+	And xs[0].t = <t0>
+	And xs[1].t = <t1>
+
+	Examples:
+		| origin          | direction           | t0      | t1       |
+		| point(0, 0, -5) | vector(0, 0, 1)     | 5       |  5       |
+		| point(0, 0, -5) | vector(1, 1, 1)     | 8.66025 |  8.66025 |
+		| point(1, 1, -5) | vector(-0.5, -1, 1) | 4.55006 | 49.44994 |
+*/
+func TestIntersectCone(t *testing.T) {
+	examples := []struct {
+		origin    Tuple
+		direction Tuple
+		t0        float64
+		t1        float64
+	}{
+		{NewPoint(0, 0, -5), NewVector(0, 0, 1), 5, 5},
+		{NewPoint(0, 0, -5), NewVector(1, 1, 1), 8.66025, 8.66025},
+		{NewPoint(1, 1, -5), NewVector(-0.5, -1, 1), 4.55006, 49.44994},
+	}
+	shape := NewCone()
+	for _, e := range examples {
+		xs := shape.LocalIntersect(Ray{e.origin, e.direction.Norm()})
+		require.Equal(t, 2, len(xs))
+		assert.InDelta(t, e.t0, xs[0].T, 0.0001)
+		assert.InDelta(t, e.t1, xs[1].T, 0.0001)
+	}
+}
+
+/*
+	Scenario: Intersecting a cone with a ray parallel to one of its halves
+	Given shape ← cone()
+	And direction ← normalize(vector(0, 1, 1))
+	And r ← ray(point(0, 0, -1), direction)
+	When xs ← local_intersect(shape, r)
+	Then xs.count = 1
+	And xs[0].t = 0.35355
+*/
+func TestIntersectConeParallelToHalf(t *testing.T) {
+	shape := NewCone()
+	r := Ray{NewPoint(0, 0, -1), NewVector(0, 1, 1).Norm()}
+	xs := shape.LocalIntersect(r)
+	require.Equal(t, 1, len(xs))
+	assert.InDelta(t, 0.35355, xs[0].T, 0.0001)
+}
+
+/*
+	Scenario Outline: Intersecting a cone's end caps
+	Given shape ← cone()
+	And shape.minimum ← -0.5
+	And shape.maximum ← 0.5
+	And shape.closed ← true
+	And direction ← normalize(<direction>)
+	And r ← ray(<origin>, direction)
+	When xs ← local_intersect(shape, r)
+	Then xs.count = <count>
+
+	Examples:
+		| origin             | direction       | count |
+		| point(0, 0, -5)    | vector(0, 1, 0) | 0     |
+		| point(0, 0, -0.25) | vector(0, 1, 1) | 2     |
+		| point(0, 0, -0.25) | vector(0, 1, 0) | 4     |
+*/
+func TestIntersectConeCaps(t *testing.T) {
+	examples := []struct {
+		origin    Tuple
+		direction Tuple
+		count     int
+	}{
+		{NewPoint(0, 0, -5), NewVector(0, 1, 0), 0},
+		{NewPoint(0, 0, -0.25), NewVector(0, 1, 1), 2},
+		{NewPoint(0, 0, -0.25), NewVector(0, 1, 0), 4},
+	}
+	shape := NewCone()
+	shape.SetMinimum(-0.5)
+	shape.SetMaximum(0.5)
+	shape.Closed = true
+	for _, e := range examples {
+		xs := shape.LocalIntersect(Ray{e.origin, e.direction.Norm()})
+		assert.Equal(t, e.count, len(xs))
+	}
+}
+
+/*
+	Scenario: A closed cone with no truncation has no caps
+	Given shape ← cone()
+	And shape.closed ← true
+	And r ← ray(point(1, 0, -5), vector(0.1, 0.3, 1))
+	When xs ← local_intersect(shape, r)
+	Then xs.count = 2
+	And xs[0].t and xs[1].t are finite
+*/
+func TestIntersectClosedInfiniteCone(t *testing.T) {
+	shape := NewCone()
+	shape.Closed = true
+	xs := shape.LocalIntersect(Ray{NewPoint(1, 0, -5), NewVector(0.1, 0.3, 1)})
+	require.Equal(t, 2, len(xs))
+	assert.False(t, math.IsInf(xs[0].T, 0))
+	assert.False(t, math.IsInf(xs[1].T, 0))
+}
+
+/*
+	Scenario Outline: Computing the normal vector on a cone
+	Given shape ← cone()
+	When n ← local_normal_at(shape, <point>)
+	Then n = <normal>
+
+	Examples:
+		| point             | normal                 |
+		| point(0, 0, 0)    | vector(0, 0, 0)        |
+		| point(1, 1, 1)    | vector(1, -√2, 1)      |
+		| point(-1, -1, 0)  | vector(-1, 1, 0)       |
+*/
+func TestConeNormal(t *testing.T) {
+	examples := []struct {
+		point  Tuple
+		normal Tuple
+	}{
+		{NewPoint(0, 0, 0), NewVector(0, 0, 0)},
+		{NewPoint(1, 1, 1), NewVector(1, -math.Sqrt2, 1)},
+		{NewPoint(-1, -1, 0), NewVector(-1, 1, 0)},
+	}
+	shape := NewCone()
+	for _, e := range examples {
+		assert.True(t, shape.LocalNormalAt(e.point, Intersection{}).Equal(e.normal))
+	}
+}
+
+/*
+	Scenario Outline: The normal vector on a cone's end caps
+	Given shape ← cone()
+	And shape.minimum ← -1
+	And shape.maximum ← 2
+	And shape.closed ← true
+	When n ← local_normal_at(shape, <point>)
+	Then n = <normal>
+
+	Examples:
+		| point             | normal           |
+		| point(0.5, -1, 0) | vector(0, -1, 0) |
+		| point(0, 2, 1.5)  | vector(0, 1, 0)  |
+		| point(1, 1, 0)    | vector(1, -1, 0) |
+*/
+func TestClosedConeCapNormal(t *testing.T) {
+	examples := []struct {
+		point  Tuple
+		normal Tuple
+	}{
+		{NewPoint(0.5, -1, 0), NewVector(0, -1, 0)},
+		{NewPoint(0, 2, 1.5), NewVector(0, 1, 0)},
+		{NewPoint(1, 1, 0), NewVector(1, -1, 0)},
+	}
+	shape := NewCone()
+	shape.SetMinimum(-1)
+	shape.SetMaximum(2)
+	shape.Closed = true
+	for _, e := range examples {
+		assert.True(t, shape.LocalNormalAt(e.point, Intersection{}).Equal(e.normal))
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"sort"
+)
+
+// CSGOperation - how a CSG combines its two shapes
+type CSGOperation int
+
+const (
+	// CSGUnion - everything in either shape
+	CSGUnion CSGOperation = iota
+	// CSGIntersection - only what is in both shapes
+	CSGIntersection
+	// CSGDifference - what is in the left shape but not the right
+	CSGDifference
+)
+
+// CSG - constructive solid geometry, two shapes combined by an operation
+type CSG struct {
+	shape
+	Operation CSGOperation
+	// left and right - fixed when the csg is made, as they are parented to it
+	// and size its bounds
+	left  Shape
+	right Shape
+
+	// bounds of both children in the csg's space
+	bounds BoundingBox
+}
+
+func NewCSG(op CSGOperation, left, right Shape) *CSG {
+	c := &CSG{
+		shape:     newShape(),
+		Operation: op,
+		left:      left,
+		right:     right,
+	}
+	left.setParent(c)
+	right.setParent(c)
+	c.boundsChanged()
+	return c
+}
+
+// Left - the first shape the operation combines
+func (c *CSG) Left() Shape {
+	return c.left
+}
+
+// Right - the second shape the operation combines
+func (c *CSG) Right() Shape {
+	return c.right
+}
+
+func (c *CSG) WithTransform(t Mat4) *CSG {
+	c.SetTransform(t)
+	return c
+}
+
+func (c *CSG) boundsChanged() {
+	c.bounds = ParentSpaceBounds(c.left).AddBox(ParentSpaceBounds(c.right))
+	if c.parent != nil {
+		c.parent.boundsChanged()
+	}
+}
+
+// Bounds - box around both children, whatever the operation removes
+func (c *CSG) Bounds() BoundingBox {
+	return c.bounds
+}
+
+// intersectionAllowed - whether a hit on one side survives the operation, given
+// which side was hit and whether the ray is currently inside each side
+func intersectionAllowed(op CSGOperation, lhit, inl, inr bool) bool {
+	switch op {
+	case CSGUnion:
+		return (lhit && !inr) || (!lhit && !inl)
+	case CSGIntersection:
+		return (lhit && inr) || (!lhit && inl)
+	case CSGDifference:
+		return (lhit && !inr) || (!lhit && inl)
+	}
+	return false
+}
+
+// filterIntersections - keep only the t-sorted intersections on the surface of the combined shape
+func (c *CSG) filterIntersections(xs Intersections) Intersections {
+	// the ray starts outside both shapes, each hit on a side toggles being inside it
+	inl := false
+	inr := false
+
+	result := Intersections{}
+	for _, i := range xs {
+		lhit := includes(c.left, i.Object)
+		if intersectionAllowed(c.Operation, lhit, inl, inr) {
+			result = append(result, i)
+		}
+		if lhit {
+			inl = !inl
+		} else {
+			inr = !inr
+		}
+	}
+	return result
+}
+
+// includes - whether b is a, or is somewhere inside a
+func includes(a, b Shape) bool {
+	switch a := a.(type) {
+	case *Group:
+		for _, c := range a.children {
+			if includes(c, b) {
+				return true
+			}
+		}
+		return false
+	case *CSG:
+		return includes(a.left, b) || includes(a.right, b)
+	}
+	return a == b
+}
+
+func (c *CSG) LocalIntersect(r Ray) Intersections {
+	if !c.bounds.Intersects(r) {
+		return Intersections{}
+	}
+
+	leftxs, err := Intersect(c.left, r)
+	if err != nil {
+		panic(err)
+	}
+	rightxs, err := Intersect(c.right, r)
+	if err != nil {
+		panic(err)
+	}
+
+	xs := append(leftxs, rightxs...)
+	sort.Sort(xs)
+	return c.filterIntersections(xs)
+}
+
+// LocalNormalAt - a csg is never hit directly, only its children are
+func (c *CSG) LocalNormalAt(_ Tuple, _ Intersection) Tuple {
+	panic("normal requested for a csg, normals come from its children")
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario: CSG is created with an operation and two shapes
+	Given s1 ← sphere()
+	And s2 ← cube()
+	When c ← csg("union", s1, s2)
+	Then c.operation = "union"
+	And c.left = s1
+	And c.right = s2
+	And s1.parent = c
+	And s2.parent = c
+*/
+func TestCreateCSG(t *testing.T) {
+	s1 := NewSphere()
+	s2 := NewCube()
+	c := NewCSG(CSGUnion, s1, s2)
+	assert.Equal(t, CSGUnion, c.Operation)
+	assert.True(t, c.Left() == s1)
+	assert.True(t, c.Right() == s2)
+	assert.True(t, s1.Parent() == c)
+	assert.True(t, s2.Parent() == c)
+}
+
+/*
+	Scenario Outline: Evaluating the rule for a CSG operation
+	When result ← intersection_allowed("<op>", <lhit>, <inl>, <inr>)
+	Then result = <result>
+
+	Examples:
+		| op           | lhit  | inl   | inr   | result |
+		| union        | true  | true  | true  | false  |
+		| union        | true  | true  | false | true   |
+		| union        | true  | false | true  | false  |
+		| union        | true  | false | false | true   |
+		| union        | false | true  | true  | false  |
+		| union        | false | true  | false | false  |
+		| union        | false | false | true  | true   |
+		| union        | false | false | false | true   |
+		| intersection | true  | true  | true  | true   |
+		| intersection | true  | true  | false | false  |
+		| intersection | true  | false | true  | true   |
+		| intersection | true  | false | false | false  |
+		| intersection | false | true  | true  | true   |
+		| intersection | false | true  | false | true   |
+		| intersection | false | false | true  | false  |
+		| intersection | false | false | false | false  |
+		| difference   | true  | true  | true  | false  |
+		| difference   | true  | true  | false | true   |
+		| difference   | true  | false | true  | false  |
+		| difference   | true  | false | false | true   |
+		| difference   | false | true  | true  | true   |
+		| difference   | false | true  | false | true   |
+		| difference   | false | false | true  | false  |
+		| difference   | false | false | false | false  |
+*/
+func TestCSGIntersectionAllowed(t *testing.T) {
+	examples := []struct {
+		op     CSGOperation
+		lhit   bool
+		inl    bool
+		inr    bool
+		result bool
+	}{
+		{CSGUnion, true, true, true, false},
+		{CSGUnion, true, true, false, true},
+		{CSGUnion, true, false, true, false},
+		{CSGUnion, true, false, false, true},
+		{CSGUnion, false, true, true, false},
+		{CSGUnion, false, true, false, false},
+		{CSGUnion, false, false, true, true},
+		{CSGUnion, false, false, false, true},
+		{CSGIntersection, true, true, true, true},
+		{CSGIntersection, true, true, false, false},
+		{CSGIntersection, true, false, true, true},
+		{CSGIntersection, true, false, false, false},
+		{CSGIntersection, false, true, true, true},
+		{CSGIntersection, false, true, false, true},
+		{CSGIntersection, false, false, true, false},
+		{CSGIntersection, false, false, false, false},
+		{CSGDifference, true, true, true, false},
+		{CSGDifference, true, true, false, true},
+		{CSGDifference, true, false, true, false},
+		{CSGDifference, true, false, false, true},
+		{CSGDifference, false, true, true, true},
+		{CSGDifference, false, true, false, true},
+		{CSGDifference, false, false, true, false},
+		{CSGDifference, false, false, false, false},
+	}
+	for _, e := range examples {
+		assert.Equal(t, e.result, intersectionAllowed(e.op, e.lhit, e.inl, e.inr), "%+v", e)
+	}
+}
+
+/*
+	Scenario Outline: Filtering a list of intersections
+	Given s1 ← sphere()
+	And s2 ← cube()
+	And c ← csg("<operation>", s1, s2)
+	And xs ← intersections(1:s1, 2:s2, 3:s1, 4:s2)
+	When result ← filter_intersections(c, xs)
+	Then result.count = 2
+	And result[0] = xs[<x0>]
+	And result[1] = xs[<x1>]
+
+	Examples:
+		| operation    | x0 | x1 |
+		| union        | 0  | 3  |
+		| intersection | 1  | 2  |
+		| difference   | 0  | 1  |
+*/
+func TestCSGFilterIntersections(t *testing.T) {
+	examples := []struct {
+		op CSGOperation
+		x0 int
+		x1 int
+	}{
+		{CSGUnion, 0, 3},
+		{CSGIntersection, 1, 2},
+		{CSGDifference, 0, 1},
+	}
+	for _, e := range examples {
+		s1 := NewSphere()
+		s2 := NewCube()
+		c := NewCSG(e.op, s1, s2)
+		xs := Intersections{
+			NewIntersection(1, s1),
+			NewIntersection(2, s2),
+			NewIntersection(3, s1),
+			NewIntersection(4, s2),
+		}
+		result := c.filterIntersections(xs)
+		require.Equal(t, 2, len(result))
+		assert.Equal(t, xs[e.x0], result[0])
+		assert.Equal(t, xs[e.x1], result[1])
+	}
+}
+
+/*
+	Scenario: A ray misses a CSG object
+	Given c ← csg("union", sphere(), cube())
+	And r ← ray(point(0, 2, -5), vector(0, 0, 1))
+	When xs ← local_intersect(c, r)
+	Then xs is empty
+*/
+func TestRayMissesCSG(t *testing.T) {
+	c := NewCSG(CSGUnion, NewSphere(), NewCube())
+	r := Ray{NewPoint(0, 2, -5), NewVector(0, 0, 1)}
+	xs := c.LocalIntersect(r)
+	assert.Equal(t, 0, len(xs))
+}
+
+/*
+	Scenario: A ray hits a CSG object
+	Given s1 ← sphere()
+	And s2 ← sphere()
+	And set_transform(s2, translation(0, 0, 0.5))
+	And c ← csg("union", s1, s2)
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	When xs ← local_intersect(c, r)
+	Then xs.count = 2
+	And xs[0].t = 4
+	And xs[0].object = s1
+	And xs[1].t = 6.5
+	And xs[1].object = s2
+*/
+func TestRayHitsCSG(t *testing.T) {
+	s1 := NewSphere()
+	s2 := NewSphere().WithTransform(NewTranslation(0, 0, 0.5))
+	c := NewCSG(CSGUnion, s1, s2)
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	xs := c.LocalIntersect(r)
+	require.Equal(t, 2, len(xs))
+	assert.InDelta(t, 4.0, xs[0].T, epsilon)
+	assert.True(t, xs[0].Object == s1)
+	assert.InDelta(t, 6.5, xs[1].T, epsilon)
+	assert.True(t, xs[1].Object == s2)
+}
+
+/*
+	Scenario: A cylinder carves a hole through a cube
+	Given cyl ← cylinder() with:
+		| minimum   | -2                   |
+		| maximum   | 2                    |
+		| closed    | true                 |
+		| transform | scaling(0.5, 1, 0.5) |
+	And c ← csg("difference", cube(), cyl)
+	When xs ← intersect(c, ray(point(0, 0, -5), vector(0, 0, 1)))
+	Then xs.count = 4
+	And the ts are 4, 4.5, 5.5 and 6
+	When xs ← intersect(c, ray(point(0, 5, 0), vector(0, -1, 0)))
+	Then xs is empty
+	When xs ← intersect(c, ray(point(0.75, 5, 0), vector(0, -1, 0)))
+	Then xs.count = 2
+*/
+func TestCSGCylinderHoleInCube(t *testing.T) {
+	// closed and longer than the cube, so a ray down the axis still enters it
+	cyl := NewCylinder().WithTransform(NewScaling(0.5, 1, 0.5))
+	cyl.SetMinimum(-2)
+	cyl.SetMaximum(2)
+	cyl.Closed = true
+	c := NewCSG(CSGDifference, NewCube(), cyl)
+
+	xs, err := Intersect(c, Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)})
+	require.Nil(t, err)
+	require.Equal(t, 4, len(xs))
+	assert.InDelta(t, 4.0, xs[0].T, epsilon)
+	assert.InDelta(t, 4.5, xs[1].T, epsilon)
+	assert.InDelta(t, 5.5, xs[2].T, epsilon)
+	assert.InDelta(t, 6.0, xs[3].T, epsilon)
+
+	// straight down the hole
+	xs, err = Intersect(c, Ray{NewPoint(0, 5, 0), NewVector(0, -1, 0)})
+	require.Nil(t, err)
+	assert.Equal(t, 0, len(xs))
+
+	// beside the hole
+	xs, err = Intersect(c, Ray{NewPoint(0.75, 5, 0), NewVector(0, -1, 0)})
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(xs))
+}
+
+/*
+	Scenario: The intersection of two spheres makes a lens
+	Given s1 ← sphere() with:
+		| transform | translation(0, 0, -0.5) |
+	And s2 ← sphere() with:
+		| transform | translation(0, 0, 0.5) |
+	And lens ← csg("intersection", s1, s2)
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	When xs ← intersect(lens, r)
+	Then xs.count = 2
+	And xs[0].t = 4.5
+	And xs[0].object = s2
+	And xs[1].t = 5.5
+	And xs[1].object = s1
+	And normal_at(s2, position(r, xs[0].t)) = vector(0, 0, -1)
+*/
+func TestCSGLens(t *testing.T) {
+	s1 := NewSphere().WithTransform(NewTranslation(0, 0, -0.5))
+	s2 := NewSphere().WithTransform(NewTranslation(0, 0, 0.5))
+	lens := NewCSG(CSGIntersection, s1, s2)
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	xs, err := Intersect(lens, r)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(xs))
+	assert.InDelta(t, 4.5, xs[0].T, epsilon)
+	assert.True(t, xs[0].Object == s2)
+	assert.InDelta(t, 5.5, xs[1].T, epsilon)
+	assert.True(t, xs[1].Object == s1)
+
+	n := NormalAt(s2, r.Position(xs[0].T), xs[0])
+	assert.True(t, n.Equal(NewVector(0, 0, -1)))
+}
+
+/*
+	Scenario: A CSG child inside a group is still found on its side
+	Given g ← group() containing s1 ← sphere()
+	And s2 ← sphere() with:
+		| transform | translation(0, 0, 0.5) |
+	And c ← csg("difference", g, s2)
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	When xs ← intersect(c, r)
+	Then xs.count = 2
+	And xs[0].t = 4
+	And xs[1].t = 4.5
+*/
+func TestCSGIncludesGroupChildren(t *testing.T) {
+	s1 := NewSphere()
+	g := NewGroup()
+	g.AddChild(s1)
+	s2 := NewSphere().WithTransform(NewTranslation(0, 0, 0.5))
+	c := NewCSG(CSGDifference, g, s2)
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	xs, err := Intersect(c, r)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(xs))
+	assert.InDelta(t, 4.0, xs[0].T, epsilon)
+	assert.InDelta(t, 4.5, xs[1].T, epsilon)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math"
+)
+
+// Cube - axis aligned box from -1 to 1 on every axis
+type Cube struct {
+	shape
+}
+
+func NewCube() *Cube {
+	return &Cube{newShape()}
+}
+
+func (c *Cube) WithTransform(t Mat4) *Cube {
+	c.SetTransform(t)
+	return c
+}
+
+func (c *Cube) WithMaterial(m Material) *Cube {
+	c.SetMaterial(m)
+	return c
+}
+
+// checkAxis - t at which the ray enters and leaves the slab between min and max on one axis
+func checkAxis(origin, direction, min, max float64) (float64, float64) {
+	if math.Abs(direction) < epsilon {
+		// parallel to the slab, either always inside it or never. an empty
+		// interval has its start after its end
+		if origin >= min && origin <= max {
+			return math.Inf(-1), math.Inf(1)
+		}
+		return math.Inf(1), math.Inf(-1)
+	}
+
+	tmin := (min - origin) / direction
+	tmax := (max - origin) / direction
+	if tmin > tmax {
+		return tmax, tmin
+	}
+	return tmin, tmax
+}
+
+func (c *Cube) LocalIntersect(r Ray) Intersections {
+	xtmin, xtmax := checkAxis(r.Origin.X, r.Direction.X, -1, 1)
+	ytmin, ytmax := checkAxis(r.Origin.Y, r.Direction.Y, -1, 1)
+	ztmin, ztmax := checkAxis(r.Origin.Z, r.Direction.Z, -1, 1)
+
+	// the ray is inside the box between the last slab entered and the first slab left
+	tmin := math.Max(xtmin, math.Max(ytmin, ztmin))
+	tmax := math.Min(xtmax, math.Min(ytmax, ztmax))
+
+	if tmin > tmax {
+		return Intersections{}
+	}
+	return Intersections{NewIntersection(tmin, c), NewIntersection(tmax, c)}
+}
+
+func (c *Cube) LocalNormalAt(p Tuple, _ Intersection) Tuple {
+	// the face is on whichever axis the point is furthest along
+	ax, ay, az := math.Abs(p.X), math.Abs(p.Y), math.Abs(p.Z)
+	maxc := math.Max(ax, math.Max(ay, az))
+
+	if maxc == ax {
+		return NewVector(p.X, 0, 0)
+	} else if maxc == ay {
+		return NewVector(0, p.Y, 0)
+	}
+	return NewVector(0, 0, p.Z)
+}
+
+func (c *Cube) Bounds() BoundingBox {
+	return NewBoundingBoxFrom(NewPoint(-1, -1, -1), NewPoint(1, 1, 1))
+}
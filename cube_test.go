@@ -0,0 +1,187 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario Outline: A ray intersects a cube
+	Given c ← cube()
+	And r ← ray(<origin>, <direction>)
+	When xs ← local_intersect(c, r)
+	Then xs.count = 2
+	And xs[0].t = <t1>
+	And xs[1].t = <t2>
+
+	Examples:
+		|        | origin            | direction        | t1 | t2 |
+		| +x     | point(5, 0.5, 0)  | vector(-1, 0, 0) |  4 |  6 |
+		| -x     | point(-5, 0.5, 0) | vector(1, 0, 0)  |  4 |  6 |
+		| +y     | point(0.5, 5, 0)  | vector(0, -1, 0) |  4 |  6 |
+		| -y     | point(0.5, -5, 0) | vector(0, 1, 0)  |  4 |  6 |
+		| +z     | point(0.5, 0, 5)  | vector(0, 0, -1) |  4 |  6 |
+		| -z     | point(0.5, 0, -5) | vector(0, 0, 1)  |  4 |  6 |
+		| inside | point(0, 0.5, 0)  | vector(0, 0, 1)  | -1 |  1 |
+*/
+func TestRayIntersectsCube(t *testing.T) {
+	examples := []struct {
+		origin    Tuple
+		direction Tuple
+		t1        float64
+		t2        float64
+	}{
+		{NewPoint(5, 0.5, 0), NewVector(-1, 0, 0), 4, 6},
+		{NewPoint(-5, 0.5, 0), NewVector(1, 0, 0), 4, 6},
+		{NewPoint(0.5, 5, 0), NewVector(0, -1, 0), 4, 6},
+		{NewPoint(0.5, -5, 0), NewVector(0, 1, 0), 4, 6},
+		{NewPoint(0.5, 0, 5), NewVector(0, 0, -1), 4, 6},
+		{NewPoint(0.5, 0, -5), NewVector(0, 0, 1), 4, 6},
+		{NewPoint(0, 0.5, 0), NewVector(0, 0, 1), -1, 1},
+	}
+	c := NewCube()
+	for _, e := range examples {
+		xs := c.LocalIntersect(Ray{e.origin, e.direction})
+		require.Equal(t, 2, len(xs))
+		assert.Equal(t, e.t1, xs[0].T)
+		assert.Equal(t, e.t2, xs[1].T)
+	}
+}
+
+/*
+	Scenario Outline: A ray misses a cube
+	Given c ← cube()
+	And r ← ray(<origin>, <direction>)
+	When xs ← local_intersect(c, r)
+	Then xs.count = 0
+
+	Examples:
+		| origin           | direction                      |
+		| point(-2, 0, 0)  | vector(0.2673, 0.5345, 0.8018) |
+		| point(0, -2, 0)  | vector(0.8018, 0.2673, 0.5345) |
+		| point(0, 0, -2)  | vector(0.5345, 0.8018, 0.2673) |
+		| point(2, 0, 2)   | vector(0, 0, -1)               |
+		| point(0, 2, 2)   | vector(0, -1, 0)               |
+		| point(2, 2, 0)   | vector(-1, 0, 0)               |
+*/
+func TestRayMissesCube(t *testing.T) {
+	examples := []struct {
+		origin    Tuple
+		direction Tuple
+	}{
+		{NewPoint(-2, 0, 0), NewVector(0.2673, 0.5345, 0.8018)},
+		{NewPoint(0, -2, 0), NewVector(0.8018, 0.2673, 0.5345)},
+		{NewPoint(0, 0, -2), NewVector(0.5345, 0.8018, 0.2673)},
+		{NewPoint(2, 0, 2), NewVector(0, 0, -1)},
+		{NewPoint(0, 2, 2), NewVector(0, -1, 0)},
+		{NewPoint(2, 2, 0), NewVector(-1, 0, 0)},
+	}
+	c := NewCube()
+	for _, e := range examples {
+		xs := c.LocalIntersect(Ray{e.origin, e.direction})
+		assert.Equal(t, 0, len(xs))
+	}
+}
+
+/*
+	Scenario Outline: The normal on the surface of a cube
+	Given c ← cube()
+	And p ← <point>
+	When normal ← local_normal_at(c, p)
+	Then normal = <normal>
+
+	Examples:
+		| point                | normal           |
+		| point(1, 0.5, -0.8)  | vector(1, 0, 0)  |
+		| point(-1, -0.2, 0.9) | vector(-1, 0, 0) |
+		| point(-0.4, 1, -0.1) | vector(0, 1, 0)  |
+		| point(0.3, -1, -0.7) | vector(0, -1, 0) |
+		| point(-0.6, 0.3, 1)  | vector(0, 0, 1)  |
+		| point(0.4, 0.4, -1)  | vector(0, 0, -1) |
+		| point(1, 1, 1)       | vector(1, 0, 0)  |
+		| point(-1, -1, -1)    | vector(-1, 0, 0) |
+*/
+func TestCubeNormal(t *testing.T) {
+	examples := []struct {
+		point  Tuple
+		normal Tuple
+	}{
+		{NewPoint(1, 0.5, -0.8), NewVector(1, 0, 0)},
+		{NewPoint(-1, -0.2, 0.9), NewVector(-1, 0, 0)},
+		{NewPoint(-0.4, 1, -0.1), NewVector(0, 1, 0)},
+		{NewPoint(0.3, -1, -0.7), NewVector(0, -1, 0)},
+		{NewPoint(-0.6, 0.3, 1), NewVector(0, 0, 1)},
+		{NewPoint(0.4, 0.4, -1), NewVector(0, 0, -1)},
+		{NewPoint(1, 1, 1), NewVector(1, 0, 0)},
+		{NewPoint(-1, -1, -1), NewVector(-1, 0, 0)},
+	}
+	c := NewCube()
+	for _, e := range examples {
+		assert.True(t, c.LocalNormalAt(e.point, Intersection{}).Equal(e.normal))
+	}
+}
+
+/*
+	Scenario: A ray intersects a rotated cube
+	Given c ← cube()
+	And set_transform(c, rotation_y(π/4))
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	When xs ← intersect(c, r)
+	Then xs.count = 2
+	And xs[0].t = 5 - √2
+	And xs[1].t = 5 + √2
+	And normal_at(c, r.position(xs[0].t)) = vector(-√2/2, 0, -√2/2)
+*/
+func TestRayIntersectsRotatedCube(t *testing.T) {
+	c := NewCube().WithTransform(NewRotationY(math.Pi / 4))
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	xs, err := Intersect(c, r)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(xs))
+	assert.InDelta(t, 5-math.Sqrt2, xs[0].T, epsilon)
+	assert.InDelta(t, 5+math.Sqrt2, xs[1].T, epsilon)
+	n := NormalAt(c, r.Position(xs[0].T), Intersection{})
+	assert.True(t, n.Equal(NewVector(-math.Sqrt2/2, 0, -math.Sqrt2/2)))
+}
+
+/*
+	Scenario: A ray intersects a sheared cube
+	Given c ← cube()
+	And set_transform(c, shearing(1, 0, 0, 0, 0, 0))
+	And r ← ray(point(-5, 0.5, 0), vector(1, 0, 0))
+	When xs ← intersect(c, r)
+	Then xs.count = 2
+	And xs[0].t = 4.5
+	And xs[1].t = 6.5
+*/
+func TestRayIntersectsShearedCube(t *testing.T) {
+	c := NewCube().WithTransform(NewShearing(1, 0, 0, 0, 0, 0))
+	r := Ray{NewPoint(-5, 0.5, 0), NewVector(1, 0, 0)}
+	xs, err := Intersect(c, r)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(xs))
+	assert.InDelta(t, 4.5, xs[0].T, epsilon)
+	assert.InDelta(t, 6.5, xs[1].T, epsilon)
+}
+
+/*
+	Scenario: A ray lying in the plane of a face
+	Given c ← cube()
+	And r ← ray(point(-1, 0, -5), vector(0, 0, 1))
+	When xs ← intersect(c, r)
+	Then xs.count = 2
+	And xs[0].t = 4
+	And xs[1].t = 6
+*/
+func TestRayInCubeFacePlane(t *testing.T) {
+	c := NewCube()
+	r := Ray{NewPoint(-1, 0, -5), NewVector(0, 0, 1)}
+	xs, err := Intersect(c, r)
+	require.Nil(t, err)
+	require.Equal(t, 2, len(xs))
+	assert.Equal(t, 4.0, xs[0].T)
+	assert.Equal(t, 6.0, xs[1].T)
+}
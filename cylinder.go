@@ -0,0 +1,136 @@
+package main
+
+import (
+	"math"
+)
+
+// Cylinder - radius 1 cylinder around the y axis, optionally truncated and capped
+type Cylinder struct {
+	shape
+	// minimum and maximum - y values the cylinder is truncated at, exclusive.
+	// They size its bounds, so they are only changed through the setters that
+	// tell any group holding it
+	minimum float64
+	maximum float64
+	Closed  bool
+}
+
+// NewCylinder - infinitely long, open cylinder
+func NewCylinder() *Cylinder {
+	return &Cylinder{
+		shape:   newShape(),
+		minimum: math.Inf(-1),
+		maximum: math.Inf(1),
+	}
+}
+
+func (c *Cylinder) WithTransform(t Mat4) *Cylinder {
+	c.SetTransform(t)
+	return c
+}
+
+func (c *Cylinder) WithMaterial(m Material) *Cylinder {
+	c.SetMaterial(m)
+	return c
+}
+
+func (c *Cylinder) Minimum() float64 {
+	return c.minimum
+}
+
+func (c *Cylinder) Maximum() float64 {
+	return c.maximum
+}
+
+// SetMinimum - truncate the cylinder below y, updating the bounds of its parent
+func (c *Cylinder) SetMinimum(y float64) {
+	c.minimum = y
+	if c.parent != nil {
+		c.parent.boundsChanged()
+	}
+}
+
+// SetMaximum - truncate the cylinder above y, updating the bounds of its parent
+func (c *Cylinder) SetMaximum(y float64) {
+	c.maximum = y
+	if c.parent != nil {
+		c.parent.boundsChanged()
+	}
+}
+
+func (c *Cylinder) LocalIntersect(r Ray) Intersections {
+	xs := Intersections{}
+
+	a := square(r.Direction.X) + square(r.Direction.Z)
+
+	// parallel to the y axis the ray can only hit the caps
+	if math.Abs(a) >= epsilon {
+		b := 2*r.Origin.X*r.Direction.X + 2*r.Origin.Z*r.Direction.Z
+		c2 := square(r.Origin.X) + square(r.Origin.Z) - 1
+		disc := b*b - 4*a*c2
+		if disc < 0 {
+			return xs
+		}
+
+		t0 := (-b - math.Sqrt(disc)) / (2 * a)
+		t1 := (-b + math.Sqrt(disc)) / (2 * a)
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+
+		y0 := r.Origin.Y + t0*r.Direction.Y
+		if c.minimum < y0 && y0 < c.maximum {
+			xs = append(xs, NewIntersection(t0, c))
+		}
+		y1 := r.Origin.Y + t1*r.Direction.Y
+		if c.minimum < y1 && y1 < c.maximum {
+			xs = append(xs, NewIntersection(t1, c))
+		}
+	}
+
+	return c.intersectCaps(r, xs)
+}
+
+func (c *Cylinder) intersectCaps(r Ray, xs Intersections) Intersections {
+	if !c.Closed || math.Abs(r.Direction.Y) < epsilon {
+		return xs
+	}
+
+	// an end left infinite has no cap
+	if !math.IsInf(c.minimum, 0) {
+		t := (c.minimum - r.Origin.Y) / r.Direction.Y
+		if checkCap(r, t, 1) {
+			xs = append(xs, NewIntersection(t, c))
+		}
+	}
+	if !math.IsInf(c.maximum, 0) {
+		t := (c.maximum - r.Origin.Y) / r.Direction.Y
+		if checkCap(r, t, 1) {
+			xs = append(xs, NewIntersection(t, c))
+		}
+	}
+	return xs
+}
+
+// checkCap - whether the ray at t is within radius of the y axis
+func checkCap(r Ray, t, radius float64) bool {
+	x := r.Origin.X + t*r.Direction.X
+	z := r.Origin.Z + t*r.Direction.Z
+	return square(x)+square(z) <= square(radius)
+}
+
+func (c *Cylinder) LocalNormalAt(p Tuple, _ Intersection) Tuple {
+	dist := square(p.X) + square(p.Z)
+
+	if dist < 1 && p.Y >= c.maximum-epsilon {
+		return NewVector(0, 1, 0)
+	}
+	if dist < 1 && p.Y <= c.minimum+epsilon {
+		return NewVector(0, -1, 0)
+	}
+	return NewVector(p.X, 0, p.Z)
+}
+
+func (c *Cylinder) Bounds() BoundingBox {
+	return NewBoundingBoxFrom(NewPoint(-1, c.minimum, -1), NewPoint(1, c.maximum, 1))
+}
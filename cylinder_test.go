@@ -0,0 +1,266 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario Outline: A ray misses a cylinder
+	Given cyl ← cylinder()
+	And direction ← normalize(<direction>)
+	And r ← ray(<origin>, direction)
+	When xs ← local_intersect(cyl, r)
+	Then xs.count = 0
+
+	Examples:
+		| origin          | direction       |
+		| point(1, 0, 0)  | vector(0, 1, 0) |
+		| point(0, 0, 0)  | vector(0, 1, 0) |
+		| point(0, 0, -5) | vector(1, 1, 1) |
+*/
+func TestRayMissesCylinder(t *testing.T) {
+	examples := []struct {
+		origin    Tuple
+		direction Tuple
+	}{
+		{NewPoint(1, 0, 0), NewVector(0, 1, 0)},
+		{NewPoint(0, 0, 0), NewVector(0, 1, 0)},
+		{NewPoint(0, 0, -5), NewVector(1, 1, 1)},
+	}
+	cyl := NewCylinder()
+	for _, e := range examples {
+		xs := cyl.LocalIntersect(Ray{e.origin, e.direction.Norm()})
+		assert.Equal(t, 0, len(xs))
+	}
+}
+
+/*
+	Scenario Outline: A ray strikes a cylinder
+	Given cyl ← cylinder()
+	And direction ← normalize(<direction>)
+	And r ← ray(<origin>, direction)
+	When xs ← local_intersect(cyl, r)
+	Then xs.count = 2
+	And xs[0].t = <t0>
+	And xs[1].t = <t1>
+
+	Examples:
+		| origin            | direction         | t0      | t1      |
+		| point(1, 0, -5)   | vector(0, 0, 1)   | 5       | 5       |
+		| point(0, 0, -5)   | vector(0, 0, 1)   | 4       | 6       |
+		| point(0.5, 0, -5) | vector(0.1, 1, 1) | 6.80798 | 7.08872 |
+*/
+func TestRayStrikesCylinder(t *testing.T) {
+	examples := []struct {
+		origin    Tuple
+		direction Tuple
+		t0        float64
+		t1        float64
+	}{
+		{NewPoint(1, 0, -5), NewVector(0, 0, 1), 5, 5},
+		{NewPoint(0, 0, -5), NewVector(0, 0, 1), 4, 6},
+		{NewPoint(0.5, 0, -5), NewVector(0.1, 1, 1), 6.80798, 7.08872},
+	}
+	cyl := NewCylinder()
+	for _, e := range examples {
+		xs := cyl.LocalIntersect(Ray{e.origin, e.direction.Norm()})
+		require.Equal(t, 2, len(xs))
+		assert.InDelta(t, e.t0, xs[0].T, 0.0001)
+		assert.InDelta(t, e.t1, xs[1].T, 0.0001)
+	}
+}
+
+/*
+	Scenario Outline: Normal vector on a cylinder
+	Given cyl ← cylinder()
+	When n ← local_normal_at(cyl, <point>)
+	Then n = <normal>
+
+	Examples:
+		| point           | normal           |
+		| point(1, 0, 0)  | vector(1, 0, 0)  |
+		| point(0, 5, -1) | vector(0, 0, -1) |
+		| point(0, -2, 1) | vector(0, 0, 1)  |
+		| point(-1, 1, 0) | vector(-1, 0, 0) |
+*/
+func TestCylinderNormal(t *testing.T) {
+	examples := []struct {
+		point  Tuple
+		normal Tuple
+	}{
+		{NewPoint(1, 0, 0), NewVector(1, 0, 0)},
+		{NewPoint(0, 5, -1), NewVector(0, 0, -1)},
+		{NewPoint(0, -2, 1), NewVector(0, 0, 1)},
+		{NewPoint(-1, 1, 0), NewVector(-1, 0, 0)},
+	}
+	cyl := NewCylinder()
+	for _, e := range examples {
+		assert.True(t, cyl.LocalNormalAt(e.point, Intersection{}).Equal(e.normal))
+	}
+}
+
+/*
+	Scenario: The default minimum and maximum for a cylinder
+	Given cyl ← cylinder()
+	Then cyl.minimum = -infinity
+	And cyl.maximum = infinity
+*/
+func TestCylinderDefaultMinMax(t *testing.T) {
+	cyl := NewCylinder()
+	assert.True(t, math.IsInf(cyl.Minimum(), -1))
+	assert.True(t, math.IsInf(cyl.Maximum(), 1))
+}
+
+/*
+	Scenario Outline: Intersecting a constrained cylinder
+	Given cyl ← cylinder()
+	And cyl.minimum ← 1
+	And cyl.maximum ← 2
+	And direction ← normalize(<direction>)
+	And r ← ray(<point>, direction)
+	When xs ← local_intersect(cyl, r)
+	Then xs.count = <count>
+
+	Examples:
+		|   | point             | direction         | count |
+		| 1 | point(0, 1.5, 0)  | vector(0.1, 1, 0) | 0     |
+		| 2 | point(0, 3, -5)   | vector(0, 0, 1)   | 0     |
+		| 3 | point(0, 0, -5)   | vector(0, 0, 1)   | 0     |
+		| 4 | point(0, 2, -5)   | vector(0, 0, 1)   | 0     |
+		| 5 | point(0, 1, -5)   | vector(0, 0, 1)   | 0     |
+		| 6 | point(0, 1.5, -2) | vector(0, 0, 1)   | 2     |
+*/
+func TestIntersectConstrainedCylinder(t *testing.T) {
+	examples := []struct {
+		point     Tuple
+		direction Tuple
+		count     int
+	}{
+		{NewPoint(0, 1.5, 0), NewVector(0.1, 1, 0), 0},
+		{NewPoint(0, 3, -5), NewVector(0, 0, 1), 0},
+		{NewPoint(0, 0, -5), NewVector(0, 0, 1), 0},
+		{NewPoint(0, 2, -5), NewVector(0, 0, 1), 0},
+		{NewPoint(0, 1, -5), NewVector(0, 0, 1), 0},
+		{NewPoint(0, 1.5, -2), NewVector(0, 0, 1), 2},
+	}
+	cyl := NewCylinder()
+	cyl.SetMinimum(1)
+	cyl.SetMaximum(2)
+	for _, e := range examples {
+		xs := cyl.LocalIntersect(Ray{e.point, e.direction.Norm()})
+		assert.Equal(t, e.count, len(xs))
+	}
+}
+
+/*
+	Scenario: The default closed value for a cylinder
+	Given cyl ← cylinder()
+	Then cyl.closed = false
+*/
+func TestCylinderDefaultClosed(t *testing.T) {
+	cyl := NewCylinder()
+	assert.False(t, cyl.Closed)
+}
+
+/*
+	Scenario Outline: Intersecting the caps of a closed cylinder
+	Given cyl ← cylinder()
+	And cyl.minimum ← 1
+	And cyl.maximum ← 2
+	And cyl.closed ← true
+	And direction ← normalize(<direction>)
+	And r ← ray(<point>, direction)
+	When xs ← local_intersect(cyl, r)
+	Then xs.count = <count>
+
+	Examples:
+		|   | point            | direction          | count |
+		| 1 | point(0, 3, 0)   | vector(0, -1, 0)   | 2     |
+		| 2 | point(0, 3, -2)  | vector(0, -1, 2)   | 2     |
+		| 3 | point(0, 4, -2)  | vector(0, -1, 1)   | 2     |
+		| 4 | point(0, 0, -2)  | vector(0, 1, 2)    | 2     |
+		| 5 | point(0, -1, -2) | vector(0, 1, 1)    | 2     |
+*/
+func TestIntersectClosedCylinderCaps(t *testing.T) {
+	examples := []struct {
+		point     Tuple
+		direction Tuple
+		count     int
+	}{
+		{NewPoint(0, 3, 0), NewVector(0, -1, 0), 2},
+		{NewPoint(0, 3, -2), NewVector(0, -1, 2), 2},
+		{NewPoint(0, 4, -2), NewVector(0, -1, 1), 2},
+		{NewPoint(0, 0, -2), NewVector(0, 1, 2), 2},
+		{NewPoint(0, -1, -2), NewVector(0, 1, 1), 2},
+	}
+	cyl := NewCylinder()
+	cyl.SetMinimum(1)
+	cyl.SetMaximum(2)
+	cyl.Closed = true
+	for _, e := range examples {
+		xs := cyl.LocalIntersect(Ray{e.point, e.direction.Norm()})
+		assert.Equal(t, e.count, len(xs))
+	}
+}
+
+/*
+	Scenario: A closed cylinder only has a cap at a finite end
+	Given cyl ← cylinder()
+	And cyl.minimum ← 1
+	And cyl.closed ← true
+	And r ← ray(point(0, 3, 0), vector(0, -1, 0))
+	When xs ← local_intersect(cyl, r)
+	Then xs.count = 1
+	And xs[0].t = 2
+*/
+func TestIntersectClosedHalfInfiniteCylinder(t *testing.T) {
+	cyl := NewCylinder()
+	cyl.SetMinimum(1)
+	cyl.Closed = true
+	xs := cyl.LocalIntersect(Ray{NewPoint(0, 3, 0), NewVector(0, -1, 0)})
+	require.Equal(t, 1, len(xs))
+	assert.Equal(t, 2.0, xs[0].T)
+}
+
+/*
+	Scenario Outline: The normal vector on a cylinder's end caps
+	Given cyl ← cylinder()
+	And cyl.minimum ← 1
+	And cyl.maximum ← 2
+	And cyl.closed ← true
+	When n ← local_normal_at(cyl, <point>)
+	Then n = <normal>
+
+	Examples:
+		| point            | normal           |
+		| point(0, 1, 0)   | vector(0, -1, 0) |
+		| point(0.5, 1, 0) | vector(0, -1, 0) |
+		| point(0, 1, 0.5) | vector(0, -1, 0) |
+		| point(0, 2, 0)   | vector(0, 1, 0)  |
+		| point(0.5, 2, 0) | vector(0, 1, 0)  |
+		| point(0, 2, 0.5) | vector(0, 1, 0)  |
+*/
+func TestClosedCylinderCapNormal(t *testing.T) {
+	examples := []struct {
+		point  Tuple
+		normal Tuple
+	}{
+		{NewPoint(0, 1, 0), NewVector(0, -1, 0)},
+		{NewPoint(0.5, 1, 0), NewVector(0, -1, 0)},
+		{NewPoint(0, 1, 0.5), NewVector(0, -1, 0)},
+		{NewPoint(0, 2, 0), NewVector(0, 1, 0)},
+		{NewPoint(0.5, 2, 0), NewVector(0, 1, 0)},
+		{NewPoint(0, 2, 0.5), NewVector(0, 1, 0)},
+	}
+	cyl := NewCylinder()
+	cyl.SetMinimum(1)
+	cyl.SetMaximum(2)
+	cyl.Closed = true
+	for _, e := range examples {
+		assert.True(t, cyl.LocalNormalAt(e.point, Intersection{}).Equal(e.normal))
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"sort"
+)
+
+// Group - collection of shapes that are transformed together. A child's
+// transform is relative to the group, so the group's transform moves them all
+type Group struct {
+	shape
+	// children - only changed through AddChild, which parents them and keeps
+	// bounds current
+	children []Shape
+
+	// bounds of every child in the group's space, kept current as children
+	// are added or transformed so rays can skip the whole group cheaply
+	bounds BoundingBox
+}
+
+func NewGroup() *Group {
+	return &Group{shape: newShape(), bounds: NewBoundingBox()}
+}
+
+func (g *Group) WithTransform(t Mat4) *Group {
+	g.SetTransform(t)
+	return g
+}
+
+// Children - the shapes in the group, a copy so the group can't be changed
+// behind its back. Use AddChild to add more
+func (g *Group) Children() []Shape {
+	return append([]Shape{}, g.children...)
+}
+
+// AddChild - add shapes to the group, placing them in the group's object space
+func (g *Group) AddChild(children ...Shape) {
+	for _, c := range children {
+		c.setParent(g)
+		g.bounds = g.bounds.AddBox(ParentSpaceBounds(c))
+	}
+	g.children = append(g.children, children...)
+	if g.parent != nil {
+		g.parent.boundsChanged()
+	}
+}
+
+func (g *Group) boundsChanged() {
+	g.bounds = NewBoundingBox()
+	for _, c := range g.children {
+		g.bounds = g.bounds.AddBox(ParentSpaceBounds(c))
+	}
+	if g.parent != nil {
+		g.parent.boundsChanged()
+	}
+}
+
+func (g *Group) Bounds() BoundingBox {
+	return g.bounds
+}
+
+func (g *Group) LocalIntersect(r Ray) Intersections {
+	if !g.bounds.Intersects(r) {
+		return Intersections{}
+	}
+
+	xs := Intersections{}
+	for _, c := range g.children {
+		cxs, err := Intersect(c, r)
+		if err != nil {
+			panic(err)
+		}
+		xs = append(xs, cxs...)
+	}
+	sort.Sort(xs)
+	return xs
+}
+
+// LocalNormalAt - groups are never hit directly, only their children are
+func (g *Group) LocalNormalAt(_ Tuple, _ Intersection) Tuple {
+	panic("normal requested for a group, normals come from its children")
+}
+
+// partitionChildren - split the children in two halves around the median
+// centroid on the axis they are most spread out along. Children with
+// infinite bounds (planes) can't be placed, they stay in the group
+func (g *Group) partitionChildren() (left, right []Shape) {
+	type entry struct {
+		shape    Shape
+		centroid Tuple
+	}
+	entries := []entry{}
+	kept := []Shape{}
+	centroids := NewBoundingBox()
+	for _, c := range g.children {
+		b := ParentSpaceBounds(c)
+		if !b.Finite() {
+			kept = append(kept, c)
+			continue
+		}
+		centroid := b.Centroid()
+		centroids = centroids.AddPoint(centroid)
+		entries = append(entries, entry{c, centroid})
+	}
+	if len(entries) < 2 {
+		return nil, nil
+	}
+
+	// median split, on the longest axis of the centroids
+	dx := centroids.Max.X - centroids.Min.X
+	dy := centroids.Max.Y - centroids.Min.Y
+	dz := centroids.Max.Z - centroids.Min.Z
+	axis := func(p Tuple) float64 { return p.X }
+	if dy > dx && dy >= dz {
+		axis = func(p Tuple) float64 { return p.Y }
+	} else if dz > dx && dz > dy {
+		axis = func(p Tuple) float64 { return p.Z }
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return axis(entries[i].centroid) < axis(entries[j].centroid)
+	})
+
+	half := len(entries) / 2
+	for i, e := range entries {
+		if i < half {
+			left = append(left, e.shape)
+		} else {
+			right = append(right, e.shape)
+		}
+	}
+	g.children = kept
+	return left, right
+}
+
+// makeSubgroup - move shapes out into a new child group
+func (g *Group) makeSubgroup(shapes []Shape) {
+	sub := NewGroup()
+	sub.AddChild(shapes...)
+	g.AddChild(sub)
+}
+
+// Divide - turn the group into a bounding volume hierarchy, recursively
+// splitting any group with more than threshold children into two subgroups.
+// Rays then only visit the subgroups whose boxes they pass through, and the
+// intersections still come back sorted by t
+func (g *Group) Divide(threshold int) {
+	if len(g.children) > threshold {
+		left, right := g.partitionChildren()
+		if len(left) > 0 {
+			g.makeSubgroup(left)
+		}
+		if len(right) > 0 {
+			g.makeSubgroup(right)
+		}
+	}
+	for _, c := range g.children {
+		divide(c, threshold)
+	}
+}
+
+// divide - divide a shape if it is a container, leave primitives alone
+func divide(s Shape, threshold int) {
+	switch s := s.(type) {
+	case *Group:
+		s.Divide(threshold)
+	case *CSG:
+		divide(s.left, threshold)
+		divide(s.right, threshold)
+	}
+}
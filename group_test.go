@@ -0,0 +1,585 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+Scenario: Creating a new group
+Given g ← group()
+Then g.transform = identity_matrix
+And g is empty
+*/
+func TestCreateGroup(t *testing.T) {
+	g := NewGroup()
+	assert.Equal(t, NewIdentityMat4(), g.Transform())
+	assert.Equal(t, 0, len(g.Children()))
+}
+
+/*
+Scenario: Adding a child to a group
+Given g ← group()
+And s ← test_shape()
+When add_child(g, s)
+Then g is not empty
+And g includes s
+*/
+func TestAddChildToGroup(t *testing.T) {
+	g := NewGroup()
+	s := newTestShape()
+	g.AddChild(s)
+	require.Equal(t, 1, len(g.Children()))
+	assert.Equal(t, s, g.Children()[0])
+	assert.True(t, s.Parent() == g)
+}
+
+/*
+Scenario: Intersecting a ray with an empty group
+Given g ← group()
+And r ← ray(point(0, 0, 0), vector(0, 0, 1))
+When xs ← local_intersect(g, r)
+Then xs is empty
+*/
+func TestIntersectEmptyGroup(t *testing.T) {
+	g := NewGroup()
+	r := Ray{NewPoint(0, 0, 0), NewVector(0, 0, 1)}
+	xs := g.LocalIntersect(r)
+	assert.Equal(t, 0, len(xs))
+}
+
+/*
+Scenario: Intersecting a ray with a nonempty group
+Given g ← group()
+And s1 ← sphere()
+And s2 ← sphere()
+And set_transform(s2, translation(0, 0, -3))
+And s3 ← sphere()
+And set_transform(s3, translation(5, 0, 0))
+And add_child(g, s1)
+And add_child(g, s2)
+And add_child(g, s3)
+When r ← ray(point(0, 0, -5), vector(0, 0, 1))
+And xs ← local_intersect(g, r)
+Then xs.count = 4
+And xs[0].object = s2
+And xs[1].object = s2
+And xs[2].object = s1
+And xs[3].object = s1
+*/
+func TestIntersectNonemptyGroup(t *testing.T) {
+	g := NewGroup()
+	s1 := NewSphere()
+	s2 := NewSphere().WithTransform(NewTranslation(0, 0, -3))
+	s3 := NewSphere().WithTransform(NewTranslation(5, 0, 0))
+	g.AddChild(s1, s2, s3)
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	xs := g.LocalIntersect(r)
+	require.Equal(t, 4, len(xs))
+	assert.True(t, xs[0].Object == s2)
+	assert.True(t, xs[1].Object == s2)
+	assert.True(t, xs[2].Object == s1)
+	assert.True(t, xs[3].Object == s1)
+}
+
+/*
+Scenario: Intersecting a transformed group
+Given g ← group()
+And set_transform(g, scaling(2, 2, 2))
+And s ← sphere()
+And set_transform(s, translation(5, 0, 0))
+And add_child(g, s)
+When r ← ray(point(10, 0, -10), vector(0, 0, 1))
+And xs ← intersect(g, r)
+Then xs.count = 2
+*/
+func TestIntersectTransformedGroup(t *testing.T) {
+	g := NewGroup().WithTransform(NewScaling(2, 2, 2))
+	s := NewSphere().WithTransform(NewTranslation(5, 0, 0))
+	g.AddChild(s)
+	r := Ray{NewPoint(10, 0, -10), NewVector(0, 0, 1)}
+	xs, err := Intersect(g, r)
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(xs))
+}
+
+/*
+Scenario: A shape has a parent attribute
+Given s ← test_shape()
+Then s.parent is nothing
+*/
+func TestShapeParentDefault(t *testing.T) {
+	s := newTestShape()
+	assert.Nil(t, s.Parent())
+}
+
+/*
+Scenario: Converting a point from world to object space
+Given g1 ← group()
+And set_transform(g1, rotation_y(π/2))
+And g2 ← group()
+And set_transform(g2, scaling(2, 2, 2))
+And add_child(g1, g2)
+And s ← sphere()
+And set_transform(s, translation(5, 0, 0))
+And add_child(g2, s)
+When p ← world_to_object(s, point(-2, 0, -10))
+Then p = point(0, 0, -1)
+*/
+func TestWorldToObject(t *testing.T) {
+	g1 := NewGroup().WithTransform(NewRotationY(math.Pi / 2))
+	g2 := NewGroup().WithTransform(NewScaling(2, 2, 2))
+	g1.AddChild(g2)
+	s := NewSphere().WithTransform(NewTranslation(5, 0, 0))
+	g2.AddChild(s)
+	p := WorldToObject(s, NewPoint(-2, 0, -10))
+	assert.True(t, p.Equal(NewPoint(0, 0, -1)))
+}
+
+/*
+Scenario: Converting a normal from object to world space
+Given g1 ← group()
+And set_transform(g1, rotation_y(π/2))
+And g2 ← group()
+And set_transform(g2, scaling(1, 2, 3))
+And add_child(g1, g2)
+And s ← sphere()
+And set_transform(s, translation(5, 0, 0))
+And add_child(g2, s)
+When n ← normal_to_world(s, vector(√3/3, √3/3, √3/3))
+Then n = vector(0.2857, 0.4286, -0.8571)
+*/
+func TestNormalToWorld(t *testing.T) {
+	g1 := NewGroup().WithTransform(NewRotationY(math.Pi / 2))
+	g2 := NewGroup().WithTransform(NewScaling(1, 2, 3))
+	g1.AddChild(g2)
+	s := NewSphere().WithTransform(NewTranslation(5, 0, 0))
+	g2.AddChild(s)
+	n := NormalToWorld(s, NewVector(math.Sqrt(3)/3, math.Sqrt(3)/3, math.Sqrt(3)/3))
+	assert.InDeltaSlice(t, []float64{0.2857, 0.4286, -0.8571}, []float64{n.X, n.Y, n.Z}, 0.0001)
+}
+
+/*
+Scenario: Finding the normal on a child object
+Given g1 ← group()
+And set_transform(g1, rotation_y(π/2))
+And g2 ← group()
+And set_transform(g2, scaling(1, 2, 3))
+And add_child(g1, g2)
+And s ← sphere()
+And set_transform(s, translation(5, 0, 0))
+And add_child(g2, s)
+When n ← normal_at(s, point(1.7321, 1.1547, -5.5774))
+Then n = vector(0.2857, 0.4286, -0.8571)
+*/
+func TestNormalOnChildObject(t *testing.T) {
+	g1 := NewGroup().WithTransform(NewRotationY(math.Pi / 2))
+	g2 := NewGroup().WithTransform(NewScaling(1, 2, 3))
+	g1.AddChild(g2)
+	s := NewSphere().WithTransform(NewTranslation(5, 0, 0))
+	g2.AddChild(s)
+	n := NormalAt(s, NewPoint(1.7321, 1.1547, -5.5774), Intersection{})
+	assert.InDeltaSlice(t, []float64{0.2857, 0.4286, -0.8571}, []float64{n.X, n.Y, n.Z}, 0.0001)
+}
+
+// hexagon - six corner spheres joined by six edge cylinders, all in one group
+func hexagon() *Group {
+	hex := NewGroup()
+	for n := 0; n < 6; n++ {
+		corner := NewSphere().WithTransform(NewScaling(0.25, 0.25, 0.25).Translate(0, 0, -1))
+
+		edge := NewCylinder()
+		edge.SetMinimum(0)
+		edge.SetMaximum(1)
+		edge.SetTransform(NewScaling(0.25, 1, 0.25).
+			RotateZ(-math.Pi/2).
+			RotateY(-math.Pi/6).
+			Translate(0, 0, -1))
+
+		side := NewGroup().WithTransform(NewRotationY(float64(n) * math.Pi / 3))
+		side.AddChild(corner, edge)
+		hex.AddChild(side)
+	}
+	return hex
+}
+
+/*
+Scenario: Moving a whole assembly with the group transform
+Given hex ← hexagon()
+And set_transform(hex, translation(0, 3, 0) * rotation_x(-π/2))
+And r ← ray(point(0, 10, 0), vector(0, -1, 0))
+When xs ← intersect(hex, r)
+And i ← hit(xs)
+Then i.object is a corner sphere
+And position(r, i.t) = point(0, 4.25, 0)
+And normal_at(i.object, position(r, i.t)) = vector(0, 1, 0)
+*/
+func TestTransformedHexagon(t *testing.T) {
+	hex := hexagon()
+	hex.SetTransform(NewRotationX(-math.Pi/2).Translate(0, 3, 0))
+
+	// the hexagon now stands upright, with the corner at z = 1 on top at y = 4
+	r := Ray{NewPoint(0, 10, 0), NewVector(0, -1, 0)}
+	xs, err := Intersect(hex, r)
+	require.Nil(t, err)
+	hit := xs.Hit()
+	require.NotNil(t, hit)
+
+	_, isSphere := hit.Object.(*Sphere)
+	assert.True(t, isSphere)
+	p := r.Position(hit.T)
+	assert.True(t, p.Equal(NewPoint(0, 4.25, 0)))
+	n := NormalAt(hit.Object, p, *hit)
+	assert.True(t, n.Equal(NewVector(0, 1, 0)))
+}
+
+/*
+	Scenario: Intersecting ray+group doesn't test children if box is missed
+	Given child ← test_shape()
+	And shape ← group()
+	And add_child(shape, child)
+	And r ← ray(point(0, 0, -5), vector(0, 1, 0))
+	When xs ← intersect(shape, r)
+	Then child.saved_ray is unset
+*/
+func TestGroupSkipsChildrenWhenBoxMissed(t *testing.T) {
+	child := newTestShape()
+	shape := NewGroup()
+	shape.AddChild(child)
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 1, 0)}
+	_, err := Intersect(shape, r)
+	require.Nil(t, err)
+	assert.Equal(t, Ray{}, child.savedRay)
+}
+
+/*
+	Scenario: Intersecting ray+group tests children if box is hit
+	Given child ← test_shape()
+	And shape ← group()
+	And add_child(shape, child)
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	When xs ← intersect(shape, r)
+	Then child.saved_ray is set
+*/
+func TestGroupTestsChildrenWhenBoxHit(t *testing.T) {
+	child := newTestShape()
+	shape := NewGroup()
+	shape.AddChild(child)
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	_, err := Intersect(shape, r)
+	require.Nil(t, err)
+	assert.Equal(t, r, child.savedRay)
+}
+
+/*
+	Scenario: Changing the slice of children doesn't change the group
+	Given g ← group()
+	And s ← sphere()
+	And add_child(g, s)
+	When children ← g.children
+	And children[0] ← cube()
+	Then g.children = [s]
+*/
+func TestGroupChildrenIsCopy(t *testing.T) {
+	g := NewGroup()
+	s := NewSphere()
+	g.AddChild(s)
+	children := g.Children()
+	children[0] = NewCube()
+	assert.Equal(t, []Shape{s}, g.Children())
+}
+
+/*
+	Scenario: Truncating a cylinder inside a group updates the group's bounds
+	Given cyl ← cylinder() with minimum 0 and maximum 1
+	And g ← group() of [cyl]
+	And r ← ray(point(0, 4, -5), vector(0, 0, 1))
+	When set_maximum(cyl, 5)
+	Then bounds_of(g).max.y = 5
+	And intersect(g, r) has 2 intersections
+*/
+func TestGroupBoundsFollowTruncation(t *testing.T) {
+	examples := []interface {
+		Shape
+		SetMinimum(float64)
+		SetMaximum(float64)
+	}{NewCylinder(), NewCone()}
+	for _, c := range examples {
+		c.SetMinimum(0)
+		c.SetMaximum(1)
+		g := NewGroup()
+		g.AddChild(c)
+		r := Ray{NewPoint(0, 4, -5), NewVector(0, 0, 1)}
+		xs, err := Intersect(g, r)
+		require.Nil(t, err)
+		assert.Len(t, xs, 0)
+
+		c.SetMaximum(5)
+		assert.Equal(t, 5.0, g.Bounds().Max.Y)
+		xs, err = Intersect(g, r)
+		require.Nil(t, err)
+		assert.Len(t, xs, 2)
+	}
+}
+
+/*
+	Scenario: Partitioning a group's children
+	Given s1 ← sphere() with:
+		| transform | translation(-2, 0, 0) |
+	And s2 ← sphere() with:
+		| transform | translation(2, 0, 0) |
+	And s3 ← sphere()
+	And g ← group() of [s1, s2, s3]
+	When (left, right) ← partition_children(g)
+	Then g is empty
+	And left = [s1]
+	And right = [s3, s2]
+*/
+func TestPartitionChildren(t *testing.T) {
+	s1 := NewSphere().WithTransform(NewTranslation(-2, 0, 0))
+	s2 := NewSphere().WithTransform(NewTranslation(2, 0, 0))
+	s3 := NewSphere()
+	g := NewGroup()
+	g.AddChild(s1, s2, s3)
+	left, right := g.partitionChildren()
+	assert.Equal(t, 0, len(g.Children()))
+	assert.Equal(t, []Shape{s1}, left)
+	assert.Equal(t, []Shape{s3, s2}, right)
+}
+
+/*
+	Scenario: Partitioning keeps unbounded children in the group
+	Given p ← plane()
+	And s1 ← sphere() with:
+		| transform | translation(-2, 0, 0) |
+	And s2 ← sphere() with:
+		| transform | translation(2, 0, 0) |
+	And g ← group() of [p, s1, s2]
+	When (left, right) ← partition_children(g)
+	Then g = [p]
+	And left = [s1]
+	And right = [s2]
+*/
+func TestPartitionChildrenKeepsUnbounded(t *testing.T) {
+	p := NewPlane()
+	s1 := NewSphere().WithTransform(NewTranslation(-2, 0, 0))
+	s2 := NewSphere().WithTransform(NewTranslation(2, 0, 0))
+	g := NewGroup()
+	g.AddChild(p, s1, s2)
+	left, right := g.partitionChildren()
+	assert.Equal(t, []Shape{p}, g.Children())
+	assert.Equal(t, []Shape{s1}, left)
+	assert.Equal(t, []Shape{s2}, right)
+}
+
+/*
+	Scenario: Creating a sub-group from a list of children
+	Given s1 ← sphere()
+	And s2 ← sphere()
+	And g ← group()
+	When make_subgroup(g, [s1, s2])
+	Then g.count = 1
+	And g[0] is a group of [s1, s2]
+*/
+func TestMakeSubgroup(t *testing.T) {
+	s1 := NewSphere()
+	s2 := NewSphere()
+	g := NewGroup()
+	g.makeSubgroup([]Shape{s1, s2})
+	require.Equal(t, 1, len(g.Children()))
+	sub, ok := g.Children()[0].(*Group)
+	require.True(t, ok)
+	assert.Equal(t, []Shape{s1, s2}, sub.Children())
+	assert.Equal(t, Shape(sub), s1.Parent())
+	assert.Equal(t, Shape(g), sub.Parent())
+}
+
+/*
+	Scenario: Subdividing a group partitions its children
+	Given s1 ← sphere() with:
+		| transform | translation(-2, -2, 0) |
+	And s2 ← sphere() with:
+		| transform | translation(-2, 2, 0) |
+	And s3 ← sphere() with:
+		| transform | scaling(4, 4, 4) |
+	And g ← group() of [s1, s2, s3]
+	When divide(g, 1)
+	Then g[0] is a group of [s1]
+	And g[1] is a group of [s2, s3] split further into [s2] and [s3]
+*/
+func TestDivideGroup(t *testing.T) {
+	s1 := NewSphere().WithTransform(NewTranslation(-2, -2, 0))
+	s2 := NewSphere().WithTransform(NewTranslation(-2, 2, 0))
+	s3 := NewSphere().WithTransform(NewScaling(4, 4, 4))
+	g := NewGroup()
+	g.AddChild(s1, s2, s3)
+	g.Divide(1)
+
+	require.Equal(t, 2, len(g.Children()))
+	left := g.Children()[0].(*Group)
+	right := g.Children()[1].(*Group)
+	assert.Equal(t, []Shape{s1}, left.Children())
+	require.Equal(t, 2, len(right.Children()))
+	assert.Equal(t, []Shape{s2}, right.Children()[0].(*Group).Children())
+	assert.Equal(t, []Shape{s3}, right.Children()[1].(*Group).Children())
+}
+
+/*
+	Scenario: Subdividing a group with too few children
+	Given s1 ← sphere() with:
+		| transform | translation(-2, 0, 0) |
+	And s2 ← sphere() with:
+		| transform | translation(2, 1, 0) |
+	And s3 ← sphere() with:
+		| transform | translation(2, -1, 0) |
+	And subgroup ← group() of [s1, s2, s3]
+	And s4 ← sphere()
+	And g ← group() of [subgroup, s4]
+	When divide(g, 2)
+	Then g[0] = subgroup
+	And g[1] = s4
+	And subgroup.count = 2
+	And subgroup[0] is a group of [s1]
+	And subgroup[1] is a group of [s2, s3]
+*/
+func TestDivideGroupTooFewChildren(t *testing.T) {
+	s1 := NewSphere().WithTransform(NewTranslation(-2, 0, 0))
+	s2 := NewSphere().WithTransform(NewTranslation(2, 1, 0))
+	s3 := NewSphere().WithTransform(NewTranslation(2, -1, 0))
+	subgroup := NewGroup()
+	subgroup.AddChild(s1, s2, s3)
+	s4 := NewSphere()
+	g := NewGroup()
+	g.AddChild(subgroup, s4)
+	g.Divide(2)
+
+	require.Equal(t, 2, len(g.Children()))
+	assert.Equal(t, Shape(subgroup), g.Children()[0])
+	assert.Equal(t, Shape(s4), g.Children()[1])
+	require.Equal(t, 2, len(subgroup.Children()))
+	assert.Equal(t, []Shape{s1}, subgroup.Children()[0].(*Group).Children())
+	assert.Equal(t, []Shape{s2, s3}, subgroup.Children()[1].(*Group).Children())
+}
+
+/*
+	Scenario: Subdividing a CSG shape subdivides its children
+	Given s1 ← sphere() with:
+		| transform | translation(-1.5, 0, 0) |
+	And s2 ← sphere() with:
+		| transform | translation(1.5, 0, 0) |
+	And left ← group() of [s1, s2]
+	And s3 ← sphere() with:
+		| transform | translation(0, 0, -1.5) |
+	And s4 ← sphere() with:
+		| transform | translation(0, 0, 1.5) |
+	And right ← group() of [s3, s4]
+	And shape ← csg("difference", left, right)
+	When divide(shape, 1)
+	Then left[0] is a group of [s1]
+	And left[1] is a group of [s2]
+	And right[0] is a group of [s3]
+	And right[1] is a group of [s4]
+*/
+func TestDivideCSG(t *testing.T) {
+	s1 := NewSphere().WithTransform(NewTranslation(-1.5, 0, 0))
+	s2 := NewSphere().WithTransform(NewTranslation(1.5, 0, 0))
+	left := NewGroup()
+	left.AddChild(s1, s2)
+	s3 := NewSphere().WithTransform(NewTranslation(0, 0, -1.5))
+	s4 := NewSphere().WithTransform(NewTranslation(0, 0, 1.5))
+	right := NewGroup()
+	right.AddChild(s3, s4)
+	shape := NewCSG(CSGDifference, left, right)
+	divide(shape, 1)
+
+	require.Equal(t, 2, len(left.Children()))
+	assert.Equal(t, []Shape{s1}, left.Children()[0].(*Group).Children())
+	assert.Equal(t, []Shape{s2}, left.Children()[1].(*Group).Children())
+	require.Equal(t, 2, len(right.Children()))
+	assert.Equal(t, []Shape{s3}, right.Children()[0].(*Group).Children())
+	assert.Equal(t, []Shape{s4}, right.Children()[1].(*Group).Children())
+}
+
+// randomSpheres - n small spheres scattered through a 20 unit cube
+func randomSpheres(n int, seed int64) []Shape {
+	rnd := rand.New(rand.NewSource(seed))
+	shapes := make([]Shape, n)
+	for i := range shapes {
+		x, y, z := rnd.Float64()*20-10, rnd.Float64()*20-10, rnd.Float64()*20-10
+		shapes[i] = NewSphere().WithTransform(NewScaling(0.2, 0.2, 0.2).Translate(x, y, z))
+	}
+	return shapes
+}
+
+/*
+	Scenario: A divided group gives the same intersections as testing every child
+	Given shapes ← 200 small spheres scattered at random
+	And w ← world() of shapes
+	And g ← group() of shapes, divided with a threshold of 4
+	Then for many random rays intersect_world(w, r) = intersect(g, r)
+*/
+func TestDividedGroupMatchesBruteForce(t *testing.T) {
+	shapes := randomSpheres(200, 1)
+	w := NewWorld()
+	w.Objects = shapes
+	g := NewGroup()
+	g.AddChild(shapes...)
+	g.Divide(4)
+
+	rnd := rand.New(rand.NewSource(2))
+	for i := 0; i < 100; i++ {
+		origin := NewPoint(rnd.Float64()*30-15, rnd.Float64()*30-15, -20)
+		target := NewPoint(rnd.Float64()*20-10, rnd.Float64()*20-10, rnd.Float64()*20-10)
+		r := Ray{origin, target.Sub(origin).Norm()}
+
+		expected, err := w.Intersect(r)
+		require.Nil(t, err)
+		actual, err := Intersect(g, r)
+		require.Nil(t, err)
+		require.Equal(t, len(expected), len(actual))
+		for j := range expected {
+			assert.Equal(t, expected[j].T, actual[j].T)
+			assert.Equal(t, expected[j].Object, actual[j].Object)
+		}
+	}
+}
+
+func benchmarkRays(n int) []Ray {
+	rnd := rand.New(rand.NewSource(3))
+	rays := make([]Ray, n)
+	for i := range rays {
+		origin := NewPoint(rnd.Float64()*30-15, rnd.Float64()*30-15, -20)
+		target := NewPoint(rnd.Float64()*20-10, rnd.Float64()*20-10, rnd.Float64()*20-10)
+		rays[i] = Ray{origin, target.Sub(origin).Norm()}
+	}
+	return rays
+}
+
+func BenchmarkIntersectBruteForce(b *testing.B) {
+	w := NewWorld()
+	w.Objects = randomSpheres(2000, 1)
+	rays := benchmarkRays(64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := w.Intersect(rays[i%len(rays)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkIntersectBVH(b *testing.B) {
+	g := NewGroup()
+	g.AddChild(randomSpheres(2000, 1)...)
+	g.Divide(4)
+	rays := benchmarkRays(64)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Intersect(g, rays[i%len(rays)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// Radiance .hdr files keep each pixel as RGBE, three 8 bit mantissas sharing
+// an exponent, so colors far brighter than 1 survive being saved. Mantissas
+// are 8 bit, values come back within about 1% of what was written
+
+// ToHDR - write canvas to given file name in Radiance RGBE format
+func (c Canvas) ToHDR(fn string) error {
+	return createImage(fn, c.WriteHDR)
+}
+
+// WriteHDR - write canvas to w in Radiance RGBE format, uncompressed.
+// Negative channels are written as 0
+func (c Canvas) WriteHDR(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n\n-Y %v +X %v\n", c.Height, c.Width)
+	for y := 0; y < c.Height; y++ {
+		for x := 0; x < c.Width; x++ {
+			rgbe := colorToRGBE(c.PixelAt(x, y))
+			bw.Write(rgbe[:])
+		}
+	}
+	return bw.Flush()
+}
+
+// LoadHDR - read the Radiance .hdr file with the given name
+func LoadHDR(fn string) (Canvas, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return Canvas{}, err
+	}
+	defer f.Close()
+
+	c, err := ParseHDR(f)
+	if err != nil {
+		return Canvas{}, fmt.Errorf("%v: %v", fn, err)
+	}
+	return c, nil
+}
+
+// ParseHDR - read a Radiance RGBE image, flat or run length encoded, into a
+// canvas. Only the usual top to bottom, left to right orientation is supported
+func ParseHDR(r io.Reader) (Canvas, error) {
+	br := bufio.NewReader(r)
+
+	// header lines run up to the first blank line
+	line, err := br.ReadString('\n')
+	if err != nil {
+		return Canvas{}, fmt.Errorf("reading header: %v", err)
+	}
+	if !strings.HasPrefix(line, "#?") {
+		return Canvas{}, fmt.Errorf("missing #? signature")
+	}
+	for {
+		line, err = br.ReadString('\n')
+		if err != nil {
+			return Canvas{}, fmt.Errorf("reading header: %v", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "FORMAT=") && line != "FORMAT=32-bit_rle_rgbe" {
+			return Canvas{}, fmt.Errorf("unsupported %v", line)
+		}
+	}
+
+	line, err = br.ReadString('\n')
+	if err != nil {
+		return Canvas{}, fmt.Errorf("reading resolution: %v", err)
+	}
+	var width, height int
+	if n, _ := fmt.Sscanf(line, "-Y %d +X %d", &height, &width); n != 2 {
+		return Canvas{}, fmt.Errorf("unsupported resolution %q", strings.TrimSpace(line))
+	}
+	if err := checkImageSize(width, height); err != nil {
+		return Canvas{}, err
+	}
+
+	c := NewCanvas(width, height)
+	scanline := make([][4]byte, width)
+	for y := 0; y < height; y++ {
+		if err := readHDRScanline(br, scanline); err != nil {
+			return Canvas{}, fmt.Errorf("scanline %v: %v", y, err)
+		}
+		for x, rgbe := range scanline {
+			c.WritePixel(x, y, rgbeToColor(rgbe))
+		}
+	}
+	return c, nil
+}
+
+// readHDRScanline - fill scanline with the next row of pixels. A row starting
+// 2, 2 followed by its width is run length encoded a channel at a time,
+// anything else is flat rgbe
+func readHDRScanline(br *bufio.Reader, scanline [][4]byte) error {
+	width := len(scanline)
+	var first [4]byte
+	if _, err := io.ReadFull(br, first[:]); err != nil {
+		return err
+	}
+	rle := width >= 8 && width < 0x8000 &&
+		first[0] == 2 && first[1] == 2 && int(first[2])<<8|int(first[3]) == width
+	if !rle {
+		scanline[0] = first
+		for x := 1; x < width; x++ {
+			if _, err := io.ReadFull(br, scanline[x][:]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for channel := 0; channel < 4; channel++ {
+		for x := 0; x < width; {
+			count, err := br.ReadByte()
+			if err != nil {
+				return err
+			}
+			run := count > 128
+			n := int(count)
+			if run {
+				n -= 128
+			}
+			if n == 0 || x+n > width {
+				return fmt.Errorf("invalid run length %v at pixel %v", n, x)
+			}
+			var value byte
+			if run {
+				if value, err = br.ReadByte(); err != nil {
+					return err
+				}
+			}
+			for i := 0; i < n; i++ {
+				if !run {
+					if value, err = br.ReadByte(); err != nil {
+						return err
+					}
+				}
+				scanline[x][channel] = value
+				x++
+			}
+		}
+	}
+	return nil
+}
+
+// colorToRGBE - the brightest channel sets the shared exponent
+func colorToRGBE(c Color) [4]byte {
+	r, g, b := math.Max(c.Red, 0), math.Max(c.Green, 0), math.Max(c.Blue, 0)
+	brightest := math.Max(r, math.Max(g, b))
+	if brightest < 1e-32 {
+		return [4]byte{}
+	}
+	mantissa, exponent := math.Frexp(brightest)
+	if exponent > 127 {
+		return [4]byte{255, 255, 255, 255}
+	}
+	scale := mantissa * 256 / brightest
+	return [4]byte{byte(r * scale), byte(g * scale), byte(b * scale), byte(exponent + 128)}
+}
+
+func rgbeToColor(rgbe [4]byte) Color {
+	if rgbe[3] == 0 {
+		return Color{}
+	}
+	f := math.Ldexp(1, int(rgbe[3])-(128+8))
+	return Color{float64(rgbe[0]) * f, float64(rgbe[1]) * f, float64(rgbe[2]) * f}
+}
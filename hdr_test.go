@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario Outline: Encoding a color as RGBE
+	Given c ← <color>
+	Then rgbe(c) = <rgbe>
+	And color(rgbe(c)) = <decoded>
+
+	Examples:
+		| color              | rgbe               | decoded            |
+		| color(0, 0, 0)     | [0, 0, 0, 0]       | color(0, 0, 0)     |
+		| color(1, 0.5, 0)   | [128, 64, 0, 129]  | color(1, 0.5, 0)   |
+		| color(10, 1, 0.25) | [160, 16, 4, 132]  | color(10, 1, 0.25) |
+		| color(-1, 2, 0)    | [0, 128, 0, 130]   | color(0, 2, 0)     |
+*/
+func TestRGBE(t *testing.T) {
+	examples := []struct {
+		color   Color
+		rgbe    [4]byte
+		decoded Color
+	}{
+		{Color{0, 0, 0}, [4]byte{0, 0, 0, 0}, Color{0, 0, 0}},
+		{Color{1, 0.5, 0}, [4]byte{128, 64, 0, 129}, Color{1, 0.5, 0}},
+		{Color{10, 1, 0.25}, [4]byte{160, 16, 4, 132}, Color{10, 1, 0.25}},
+		{Color{-1, 2, 0}, [4]byte{0, 128, 0, 130}, Color{0, 2, 0}},
+	}
+	for _, e := range examples {
+		rgbe := colorToRGBE(e.color)
+		assert.Equal(t, e.rgbe, rgbe, "%v", e.color)
+		assert.True(t, rgbeToColor(rgbe).Equal(e.decoded), "%v", e.color)
+	}
+}
+
+/*
+	Scenario: Constructing a Radiance HDR file
+	Given c ← canvas(2, 1)
+	And write_pixel(c, 0, 0, color(1, 0.5, 0))
+	And write_pixel(c, 1, 0, color(10, 1, 0.25))
+	When hdr ← canvas_to_hdr(c)
+	Then hdr starts with "#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n\n-Y 1 +X 2\n"
+	And the rest of hdr is bytes 128 64 0 129 160 16 4 132
+*/
+func TestWriteHDR(t *testing.T) {
+	c := NewCanvas(2, 1)
+	c.WritePixel(0, 0, Color{1, 0.5, 0})
+	c.WritePixel(1, 0, Color{10, 1, 0.25})
+	buf := bytes.Buffer{}
+	require.Nil(t, c.WriteHDR(&buf))
+	expected := append([]byte("#?RADIANCE\nFORMAT=32-bit_rle_rgbe\n\n-Y 1 +X 2\n"),
+		128, 64, 0, 129, 160, 16, 4, 132,
+	)
+	assert.Equal(t, expected, buf.Bytes())
+}
+
+/*
+	Scenario: Reading a run length encoded HDR file
+	Given hdr ← header "#?RGBE\nGAMMA=1\n\n-Y 1 +X 8\n" followed by a scanline
+		| 2 2 0 8                      | new style run length marker  |
+		| 136 128                      | red: 8 x 128                 |
+		| 4 64 0 64 0 132 32           | green: 64 0 64 0, 4 x 32     |
+		| 136 0                        | blue: 8 x 0                  |
+		| 136 129                      | exponent: 8 x 129            |
+	When c ← hdr_to_canvas(hdr)
+	Then pixel_at(c, 0, 0) = color(1, 0.5, 0)
+	And pixel_at(c, 1, 0) = color(1, 0, 0)
+	And pixel_at(c, 7, 0) = color(1, 0.25, 0)
+*/
+func TestParseHDRRunLength(t *testing.T) {
+	hdr := append([]byte("#?RGBE\nGAMMA=1\n\n-Y 1 +X 8\n"),
+		2, 2, 0, 8,
+		136, 128,
+		4, 64, 0, 64, 0, 132, 32,
+		136, 0,
+		136, 129,
+	)
+	c, err := ParseHDR(bytes.NewReader(hdr))
+	require.Nil(t, err)
+	assert.Equal(t, 8, c.Width)
+	assert.Equal(t, 1, c.Height)
+	assert.True(t, c.PixelAt(0, 0).Equal(Color{1, 0.5, 0}))
+	assert.True(t, c.PixelAt(1, 0).Equal(Color{1, 0, 0}))
+	assert.True(t, c.PixelAt(7, 0).Equal(Color{1, 0.25, 0}))
+}
+
+/*
+	Scenario Outline: Reading an invalid HDR file is an error
+	Given hdr ← <hdr>
+	Then hdr_to_canvas(hdr) is an error
+
+	Examples:
+		| hdr                                              | reason               |
+		| "P6\n1 1\n255\n"                                 | missing signature    |
+		| "#?RADIANCE\nFORMAT=32-bit_rle_xyze\n\n-Y 1 +X 1\n" | xyz colors       |
+		| "#?RADIANCE\n\n+Y 1 +X 1\n\x80\x80\x80\x81"      | flipped orientation  |
+		| "#?RADIANCE\n\n-Y 1 +X 2\n\x80\x80\x80\x81"      | truncated scanline   |
+		| "#?RADIANCE\n\n-Y 1 +X 8\n\x02\x02\x00\x08\x89\x01" | run past the width |
+		| "#?RADIANCE\n\n-Y 100000 +X 100000\n"          | too large to allocate |
+*/
+func TestParseHDRInvalid(t *testing.T) {
+	examples := []string{
+		"P6\n1 1\n255\n",
+		"#?RADIANCE\nFORMAT=32-bit_rle_xyze\n\n-Y 1 +X 1\n",
+		"#?RADIANCE\n\n+Y 1 +X 1\n\x80\x80\x80\x81",
+		"#?RADIANCE\n\n-Y 1 +X 2\n\x80\x80\x80\x81",
+		"#?RADIANCE\n\n-Y 1 +X 8\n\x02\x02\x00\x08\x89\x01",
+		"#?RADIANCE\n\n-Y 100000 +X 100000\n",
+	}
+	for _, hdr := range examples {
+		_, err := ParseHDR(strings.NewReader(hdr))
+		assert.NotNil(t, err, "%q", hdr)
+	}
+}
+
+/*
+	Scenario: Bright colors survive a round trip through an HDR file
+	Given c ← canvas(10, 7) with colors from 0 up to 1000
+	When canvas_to_hdr(c) is written to a file
+	And c2 ← load_hdr(file)
+	Then every channel of c2 is within 1% of c
+*/
+func TestHDRRoundTrip(t *testing.T) {
+	fn := "test.hdr"
+	c := NewCanvas(10, 7)
+	for x := 0; x < c.Width; x++ {
+		for y := 0; y < c.Height; y++ {
+			c.WritePixel(x, y, Color{float64(x*x*y) * 3.7, float64(x) / 9, 1000})
+		}
+	}
+	require.Nil(t, c.ToHDR(fn))
+	c2, err := LoadHDR(fn)
+	require.Nil(t, err)
+	require.Nil(t, os.Remove(fn))
+
+	require.Equal(t, c.Width, c2.Width)
+	require.Equal(t, c.Height, c2.Height)
+	for x := 0; x < c.Width; x++ {
+		for y := 0; y < c.Height; y++ {
+			expected, actual := c.PixelAt(x, y), c2.PixelAt(x, y)
+			// channels share the brightest one's exponent, dimmer ones lose precision
+			tolerance := 0.01 * 1000
+			assert.InDeltaSlice(t,
+				[]float64{expected.Red, expected.Green, expected.Blue},
+				[]float64{actual.Red, actual.Green, actual.Blue},
+				tolerance,
+			)
+			assert.InEpsilon(t, expected.Blue, actual.Blue, 0.01)
+		}
+	}
+
+	_, err = LoadHDR("missing.hdr")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+)
+
+// Canvas is an image.Image with 16 bits per channel, so it can be handed to
+// any encoder in the standard library. Channels are clamped to 0 to 1
+
+func (c Canvas) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+func (c Canvas) Bounds() image.Rectangle {
+	return image.Rect(0, 0, c.Width, c.Height)
+}
+
+func (c Canvas) At(x, y int) color.Color {
+	if x < 0 || x >= c.Width || y < 0 || y >= c.Height {
+		return color.RGBA64{}
+	}
+	p := c.PixelAt(x, y)
+	return color.RGBA64{
+		R: getPixelValue16(p.Red),
+		G: getPixelValue16(p.Green),
+		B: getPixelValue16(p.Blue),
+		A: 0xffff,
+	}
+}
+
+func getPixelValue16(p float64) uint16 {
+	if p <= 0 {
+		return 0
+	}
+	if p >= 1 {
+		return 0xffff
+	}
+	return uint16(math.Round(p * 0xffff))
+}
+
+// RGBA - 8 bit copy of the canvas, rounded the same way as the PPM writers
+func (c Canvas) RGBA() *image.RGBA {
+	img := image.NewRGBA(c.Bounds())
+	for y := 0; y < c.Height; y++ {
+		for x := 0; x < c.Width; x++ {
+			p := c.PixelAt(x, y)
+			img.SetRGBA(x, y, color.RGBA{
+				R: uint8(getPixelValue(p.Red)),
+				G: uint8(getPixelValue(p.Green)),
+				B: uint8(getPixelValue(p.Blue)),
+				A: 0xff,
+			})
+		}
+	}
+	return img
+}
+
+// NewCanvasFromImage - canvas holding the pixels of img, with its top left
+// corner at 0, 0. Transparency is dropped, colors are not premultiplied
+func NewCanvasFromImage(img image.Image) Canvas {
+	b := img.Bounds()
+	c := NewCanvas(b.Dx(), b.Dy())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			p := color.NRGBA64Model.Convert(img.At(x, y)).(color.NRGBA64)
+			c.WritePixel(x-b.Min.X, y-b.Min.Y, Color{
+				float64(p.R) / 0xffff,
+				float64(p.G) / 0xffff,
+				float64(p.B) / 0xffff,
+			})
+		}
+	}
+	return c
+}
+
+// LoadImage - read a PNG or JPEG file into a canvas
+func LoadImage(fn string) (Canvas, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return Canvas{}, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return Canvas{}, err
+	}
+	return NewCanvasFromImage(img), nil
+}
+
+// createImage - create fn and encode the canvas into it with write
+func createImage(fn string, write func(io.Writer) error) error {
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := write(f); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// ToPNG - write canvas to given file name as an 8 bit PNG
+func (c Canvas) ToPNG(fn string) error {
+	return createImage(fn, c.WritePNG)
+}
+
+// WritePNG - write canvas to w as an 8 bit PNG
+func (c Canvas) WritePNG(w io.Writer) error {
+	return png.Encode(w, c.RGBA())
+}
+
+// ToPNG16 - write canvas to given file name as a 16 bit PNG, which keeps
+// smooth gradients from banding
+func (c Canvas) ToPNG16(fn string) error {
+	return createImage(fn, c.WritePNG16)
+}
+
+// WritePNG16 - write canvas to w as a 16 bit PNG
+func (c Canvas) WritePNG16(w io.Writer) error {
+	return png.Encode(w, c)
+}
+
+// ToJPEG - write canvas to given file name as a JPEG, quality is 1 to 100
+func (c Canvas) ToJPEG(fn string, quality int) error {
+	return createImage(fn, func(w io.Writer) error {
+		return c.WriteJPEG(w, quality)
+	})
+}
+
+// WriteJPEG - write canvas to w as a JPEG, quality is 1 to 100
+func (c Canvas) WriteJPEG(w io.Writer, quality int) error {
+	return jpeg.Encode(w, c.RGBA(), &jpeg.Options{Quality: quality})
+}
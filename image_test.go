@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario: A canvas is an image
+	Given c ← canvas(5, 3)
+	And write_pixel(c, 0, 0, color(1.5, 0, 0))
+	And write_pixel(c, 2, 1, color(0, 0.5, 0))
+	And write_pixel(c, 4, 2, color(-0.5, 0, 1))
+	Then c.bounds = rectangle(0, 0, 5, 3)
+	And at(c, 0, 0) = rgba64(65535, 0, 0, 65535)
+	And at(c, 2, 1) = rgba64(0, 32768, 0, 65535)
+	And at(c, 4, 2) = rgba64(0, 0, 65535, 65535)
+*/
+func TestCanvasImage(t *testing.T) {
+	c := NewCanvas(5, 3)
+	c.WritePixel(0, 0, Color{1.5, 0, 0})
+	c.WritePixel(2, 1, Color{0, 0.5, 0})
+	c.WritePixel(4, 2, Color{-0.5, 0, 1})
+
+	var img image.Image = c
+	assert.Equal(t, image.Rect(0, 0, 5, 3), img.Bounds())
+	assert.Equal(t, color.RGBA64Model, img.ColorModel())
+	assert.Equal(t, color.RGBA64{0xffff, 0, 0, 0xffff}, img.At(0, 0))
+	assert.Equal(t, color.RGBA64{0, 0x8000, 0, 0xffff}, img.At(2, 1))
+	assert.Equal(t, color.RGBA64{0, 0, 0xffff, 0xffff}, img.At(4, 2))
+	assert.Equal(t, color.RGBA64{}, img.At(5, 0))
+}
+
+/*
+	Scenario: An 8 bit copy of a canvas matches its PPM values
+	Given c ← canvas(5, 3)
+	And write_pixel(c, 2, 1, color(0, 0.5, 0))
+	When img ← rgba(c)
+	Then img.at(2, 1) = rgba(0, 128, 0, 255)
+*/
+func TestCanvasRGBA(t *testing.T) {
+	c := NewCanvas(5, 3)
+	c.WritePixel(2, 1, Color{0, 0.5, 0})
+	img := c.RGBA()
+	assert.Equal(t, color.RGBA{0, 128, 0, 255}, img.RGBAAt(2, 1))
+	assert.Equal(t, color.RGBA{0, 0, 0, 255}, img.RGBAAt(0, 0))
+}
+
+/*
+	Scenario: Creating a canvas from an image
+	Given img ← nrgba image covering rectangle(2, 3, 4, 4)
+	And img.at(2, 3) = nrgba(255, 0, 51, 255)
+	And img.at(3, 3) = nrgba(0, 102, 255, 128)
+	When c ← canvas_from_image(img)
+	Then c.width = 2
+	And c.height = 1
+	And pixel_at(c, 0, 0) = color(1, 0, 0.2)
+	And pixel_at(c, 1, 0) is within 1/255 of color(0, 0.4, 1)
+*/
+func TestCanvasFromImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(2, 3, 4, 4))
+	img.SetNRGBA(2, 3, color.NRGBA{255, 0, 51, 255})
+	img.SetNRGBA(3, 3, color.NRGBA{0, 102, 255, 128})
+	c := NewCanvasFromImage(img)
+	assert.Equal(t, 2, c.Width)
+	assert.Equal(t, 1, c.Height)
+	assert.True(t, c.PixelAt(0, 0).Equal(Color{1, 0, 0.2}))
+	// translucent pixels come back premultiplied, undoing that rounds a little
+	p := c.PixelAt(1, 0)
+	assert.InDeltaSlice(t, []float64{0, 0.4, 1}, []float64{p.Red, p.Green, p.Blue}, 1.0/255)
+}
+
+// gradientCanvas - canvas fading from black to white, left to right
+func gradientCanvas(width, height int) Canvas {
+	c := NewCanvas(width, height)
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			v := float64(x) / float64(width-1)
+			c.WritePixel(x, y, Color{v, v, v})
+		}
+	}
+	return c
+}
+
+/*
+	Scenario: Writing an 8 bit PNG
+	Given c ← a gradient canvas(300, 2)
+	When png ← canvas_to_png(c)
+	Then png decodes to an 8 bit image equal to rgba(c)
+*/
+func TestWritePNG(t *testing.T) {
+	c := gradientCanvas(300, 2)
+	buf := bytes.Buffer{}
+	require.Nil(t, c.WritePNG(&buf))
+	img, err := png.Decode(&buf)
+	require.Nil(t, err)
+	assert.Equal(t, c.RGBA().Pix, img.(*image.RGBA).Pix)
+}
+
+/*
+	Scenario: Writing a 16 bit PNG keeps more than 256 levels
+	Given c ← a gradient canvas(1000, 1)
+	When png ← canvas_to_png16(c)
+	And c2 ← canvas_from_image(decode(png))
+	Then c2 has 1000 different colors
+	And every pixel of c2 is within 1/65535 of c
+*/
+func TestWritePNG16(t *testing.T) {
+	c := gradientCanvas(1000, 1)
+	buf := bytes.Buffer{}
+	require.Nil(t, c.WritePNG16(&buf))
+	img, err := png.Decode(&buf)
+	require.Nil(t, err)
+	_, ok := img.(*image.RGBA64)
+	require.True(t, ok, "expected a 16 bit image, got %T", img)
+
+	c2 := NewCanvasFromImage(img)
+	levels := map[float64]bool{}
+	for x := 0; x < c.Width; x++ {
+		levels[c2.PixelAt(x, 0).Red] = true
+		assert.InDelta(t, c.PixelAt(x, 0).Red, c2.PixelAt(x, 0).Red, 1.0/0xffff)
+	}
+	assert.Equal(t, 1000, len(levels))
+}
+
+/*
+	Scenario: Writing a JPEG
+	Given c ← canvas(16, 16) filled with color(0.2, 0.4, 0.6)
+	When jpeg ← canvas_to_jpeg(c, 95)
+	Then jpeg decodes to a 16 x 16 image
+	And every pixel is close to color(0.2, 0.4, 0.6)
+*/
+func TestWriteJPEG(t *testing.T) {
+	c := NewCanvas(16, 16)
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 16; y++ {
+			c.WritePixel(x, y, Color{0.2, 0.4, 0.6})
+		}
+	}
+	buf := bytes.Buffer{}
+	require.Nil(t, c.WriteJPEG(&buf, 95))
+	img, err := jpeg.Decode(&buf)
+	require.Nil(t, err)
+
+	c2 := NewCanvasFromImage(img)
+	require.Equal(t, 16, c2.Width)
+	require.Equal(t, 16, c2.Height)
+	p := c2.PixelAt(8, 8)
+	assert.InDeltaSlice(t, []float64{0.2, 0.4, 0.6}, []float64{p.Red, p.Green, p.Blue}, 0.02)
+}
+
+/*
+	Scenario: A canvas round trips through a PNG file
+	Given c ← a gradient canvas(20, 3)
+	When canvas_to_png16(c) is written to a file
+	And c2 ← load_image(file)
+	Then c2 = canvas_from_image(c)
+*/
+func TestPNGFileRoundTrip(t *testing.T) {
+	fn := "test.png"
+	c := gradientCanvas(20, 3)
+	require.Nil(t, c.ToPNG16(fn))
+	c2, err := LoadImage(fn)
+	require.Nil(t, err)
+	assert.Equal(t, NewCanvasFromImage(c), c2)
+	require.Nil(t, os.Remove(fn))
+
+	_, err = LoadImage("missing.png")
+	assert.NotNil(t, err)
+}
@@ -2,7 +2,18 @@ package main
 
 type Intersection struct {
 	T      float64
-	Object Sphere
+	Object Shape
+	// U, V - where on the surface the hit landed, only set by shapes that need it (triangles)
+	U float64
+	V float64
+}
+
+func NewIntersection(t float64, s Shape) Intersection {
+	return Intersection{T: t, Object: s}
+}
+
+func NewIntersectionWithUV(t float64, s Shape, u, v float64) Intersection {
+	return Intersection{t, s, u, v}
 }
 
 type Intersections []Intersection
@@ -17,3 +28,91 @@ func (is Intersections) Hit() *Intersection {
 
 	return hit
 }
+
+// Len, Less and Swap implement sort.Interface, ordering by t
+func (is Intersections) Len() int {
+	return len(is)
+}
+
+func (is Intersections) Less(i, j int) bool {
+	return is[i].T < is[j].T
+}
+
+func (is Intersections) Swap(i, j int) {
+	is[i], is[j] = is[j], is[i]
+}
+
+// Computations - everything shading needs to know about a hit, worked out
+// once by PrepareComputations
+type Computations struct {
+	T      float64
+	Object Shape
+	Point  Tuple
+	EyeV   Tuple
+	// NormalV - surface normal, flipped to face the eye when Inside
+	NormalV Tuple
+	// Inside - the ray started inside the object
+	Inside   bool
+	ReflectV Tuple
+	// OverPoint and UnderPoint - Point nudged by epsilon to either side of
+	// the surface, so rays leaving it don't hit it again straight away.
+	// OverPoint is on the eye's side, UnderPoint inside the surface
+	OverPoint  Tuple
+	UnderPoint Tuple
+	// N1 and N2 - refractive indices either side of the surface, see RefractiveIndices
+	N1 float64
+	N2 float64
+}
+
+// PrepareComputations - the state of hit along r. xs is every intersection
+// along r, sorted, for working out the refractive indices
+func PrepareComputations(hit Intersection, r Ray, xs Intersections) Computations {
+	comps := Computations{T: hit.T, Object: hit.Object}
+	comps.Point = r.Position(hit.T)
+	comps.EyeV = r.Direction.Neg()
+	comps.NormalV = NormalAt(hit.Object, comps.Point, hit)
+	// the eye is inside the object, flip the normal so it faces the eye
+	if comps.NormalV.Dot(comps.EyeV) < 0 {
+		comps.Inside = true
+		comps.NormalV = comps.NormalV.Neg()
+	}
+	comps.ReflectV = r.Direction.Reflect(comps.NormalV)
+	comps.OverPoint = comps.Point.Add(comps.NormalV.Mul(epsilon))
+	comps.UnderPoint = comps.Point.Sub(comps.NormalV.Mul(epsilon))
+	comps.N1, comps.N2 = RefractiveIndices(hit, xs)
+	return comps
+}
+
+// RefractiveIndices - refractive index of the material the ray is leaving
+// (n1) and entering (n2) at hit. xs is every intersection along the ray,
+// sorted, walking it tracks which objects the ray is inside. Empty space is 1
+func RefractiveIndices(hit Intersection, xs Intersections) (n1, n2 float64) {
+	n1, n2 = 1, 1
+	containers := []Shape{}
+	for _, i := range xs {
+		if i == hit && len(containers) > 0 {
+			n1 = containers[len(containers)-1].Material().RefractiveIndex
+		}
+
+		// the ray leaves an object it is inside, enters any other
+		found := false
+		for j, c := range containers {
+			if c == i.Object {
+				containers = append(containers[:j], containers[j+1:]...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			containers = append(containers, i.Object)
+		}
+
+		if i == hit {
+			if len(containers) > 0 {
+				n2 = containers[len(containers)-1].Material().RefractiveIndex
+			}
+			return n1, n2
+		}
+	}
+	return n1, n2
+}
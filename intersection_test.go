@@ -1,6 +1,7 @@
 package main
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -14,8 +15,8 @@ import (
 	And i.object = s
 */
 func TestIntersecionCreateQuery(t *testing.T) {
-	s := Sphere{}
-	i := Intersection{3.5, s}
+	s := NewSphere()
+	i := NewIntersection(3.5, s)
 	assert.Equal(t, 3.5, i.T)
 	assert.Equal(t, s, i.Object)
 }
@@ -31,9 +32,9 @@ func TestIntersecionCreateQuery(t *testing.T) {
 	And xs[1].t = 2
 */
 func TestAggregateIntersections(t *testing.T) {
-	s := Sphere{}
-	i1 := Intersection{1, s}
-	i2 := Intersection{2, s}
+	s := NewSphere()
+	i1 := NewIntersection(1, s)
+	i2 := NewIntersection(2, s)
 	xs := Intersections{i1, i2}
 	assert.Equal(t, 2, len(xs))
 	assert.Equal(t, 1.0, xs[0].T)
@@ -50,9 +51,9 @@ func TestAggregateIntersections(t *testing.T) {
 	Then i = i1
 */
 func TestHitAllPositive(t *testing.T) {
-	s := Sphere{}
-	i1 := Intersection{1, s}
-	i2 := Intersection{2, s}
+	s := NewSphere()
+	i1 := NewIntersection(1, s)
+	i2 := NewIntersection(2, s)
 	xs := Intersections{i2, i1}
 	i := xs.Hit()
 	assert.Equal(t, i1, *i)
@@ -68,9 +69,9 @@ func TestHitAllPositive(t *testing.T) {
 	Then i = i2
 */
 func TestHitSomeNegative(t *testing.T) {
-	s := Sphere{}
-	i1 := Intersection{-1, s}
-	i2 := Intersection{1, s}
+	s := NewSphere()
+	i1 := NewIntersection(-1, s)
+	i2 := NewIntersection(1, s)
 	xs := Intersections{i2, i1}
 	i := xs.Hit()
 	assert.Equal(t, i2, *i)
@@ -86,9 +87,9 @@ func TestHitSomeNegative(t *testing.T) {
 	Then i is nothing
 */
 func TestHitAllNegative(t *testing.T) {
-	s := Sphere{}
-	i1 := Intersection{-2, s}
-	i2 := Intersection{-1, s}
+	s := NewSphere()
+	i1 := NewIntersection(-2, s)
+	i2 := NewIntersection(-1, s)
 	xs := Intersections{i2, i1}
 	i := xs.Hit()
 	assert.Nil(t, i)
@@ -106,12 +107,246 @@ func TestHitAllNegative(t *testing.T) {
 	Then i = i4
 */
 func TestHitIsLowestNonNegative(t *testing.T) {
-	s := Sphere{}
-	i1 := Intersection{5, s}
-	i2 := Intersection{7, s}
-	i3 := Intersection{-3, s}
-	i4 := Intersection{2, s}
+	s := NewSphere()
+	i1 := NewIntersection(5, s)
+	i2 := NewIntersection(7, s)
+	i3 := NewIntersection(-3, s)
+	i4 := NewIntersection(2, s)
 	xs := Intersections{i1, i2, i3, i4}
 	i := xs.Hit()
 	assert.Equal(t, i4, *i)
 }
+
+/*
+	Scenario Outline: Finding n1 and n2 at various intersections
+	Given A ← glass_sphere() with:
+		| transform                 | scaling(2, 2, 2) |
+		| material.refractive_index | 1.5              |
+	And B ← glass_sphere() with:
+		| transform                 | translation(0, 0, -0.25) |
+		| material.refractive_index | 2.0                      |
+	And C ← glass_sphere() with:
+		| transform                 | translation(0, 0, 0.25) |
+		| material.refractive_index | 2.5                     |
+	And r ← ray(point(0, 0, -4), vector(0, 0, 1))
+	And xs ← intersections(2:A, 2.75:B, 3.25:C, 4.75:B, 5.25:C, 6:A)
+	When n1, n2 ← refractive_indices(xs[<index>], xs)
+	Then n1 = <n1>
+	And n2 = <n2>
+
+	Examples:
+		| index | n1  | n2  |
+		| 0     | 1.0 | 1.5 |
+		| 1     | 1.5 | 2.0 |
+		| 2     | 2.0 | 2.5 |
+		| 3     | 2.5 | 2.5 |
+		| 4     | 2.5 | 1.5 |
+		| 5     | 1.5 | 1.0 |
+*/
+func TestRefractiveIndices(t *testing.T) {
+	a := NewGlassSphere().WithTransform(NewScaling(2, 2, 2))
+	a.Material().RefractiveIndex = 1.5
+	b := NewGlassSphere().WithTransform(NewTranslation(0, 0, -0.25))
+	b.Material().RefractiveIndex = 2.0
+	c := NewGlassSphere().WithTransform(NewTranslation(0, 0, 0.25))
+	c.Material().RefractiveIndex = 2.5
+	xs := Intersections{
+		NewIntersection(2, a),
+		NewIntersection(2.75, b),
+		NewIntersection(3.25, c),
+		NewIntersection(4.75, b),
+		NewIntersection(5.25, c),
+		NewIntersection(6, a),
+	}
+
+	examples := []struct {
+		index int
+		n1    float64
+		n2    float64
+	}{
+		{0, 1.0, 1.5},
+		{1, 1.5, 2.0},
+		{2, 2.0, 2.5},
+		{3, 2.5, 2.5},
+		{4, 2.5, 1.5},
+		{5, 1.5, 1.0},
+	}
+	for _, e := range examples {
+		n1, n2 := RefractiveIndices(xs[e.index], xs)
+		assert.Equal(t, e.n1, n1, "n1 at %v", e.index)
+		assert.Equal(t, e.n2, n2, "n2 at %v", e.index)
+	}
+}
+
+/*
+	Scenario: A hit that isn't in xs is between empty space on both sides
+	Given s ← glass_sphere()
+	And xs ← intersections(4:s, 6:s)
+	When n1, n2 ← refractive_indices(intersection(5, s), xs)
+	Then n1 = 1.0
+	And n2 = 1.0
+*/
+func TestRefractiveIndicesHitMissing(t *testing.T) {
+	s := NewGlassSphere()
+	xs := Intersections{NewIntersection(4, s), NewIntersection(6, s)}
+	n1, n2 := RefractiveIndices(NewIntersection(5, s), xs)
+	assert.Equal(t, 1.0, n1)
+	assert.Equal(t, 1.0, n2)
+}
+
+/*
+	Scenario: Precomputing the state of an intersection
+	Given r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And shape ← sphere()
+	And i ← intersection(4, shape)
+	When comps ← prepare_computations(i, r)
+	Then comps.t = i.t
+	And comps.object = i.object
+	And comps.point = point(0, 0, -1)
+	And comps.eyev = vector(0, 0, -1)
+	And comps.normalv = vector(0, 0, -1)
+*/
+func TestPrepareComputations(t *testing.T) {
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	shape := NewSphere()
+	i := NewIntersection(4, shape)
+	comps := PrepareComputations(i, r, Intersections{i})
+	assert.Equal(t, i.T, comps.T)
+	assert.Equal(t, i.Object, comps.Object)
+	assert.True(t, comps.Point.Equal(NewPoint(0, 0, -1)))
+	assert.True(t, comps.EyeV.Equal(NewVector(0, 0, -1)))
+	assert.True(t, comps.NormalV.Equal(NewVector(0, 0, -1)))
+}
+
+/*
+	Scenario: The hit, when an intersection occurs on the outside
+	Given r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And shape ← sphere()
+	And i ← intersection(4, shape)
+	When comps ← prepare_computations(i, r)
+	Then comps.inside = false
+*/
+func TestPrepareComputationsOutside(t *testing.T) {
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	i := NewIntersection(4, NewSphere())
+	comps := PrepareComputations(i, r, Intersections{i})
+	assert.False(t, comps.Inside)
+}
+
+/*
+	Scenario: The hit, when an intersection occurs on the inside
+	Given r ← ray(point(0, 0, 0), vector(0, 0, 1))
+	And shape ← sphere()
+	And i ← intersection(1, shape)
+	When comps ← prepare_computations(i, r)
+	Then comps.point = point(0, 0, 1)
+	And comps.eyev = vector(0, 0, -1)
+	And comps.inside = true
+	# normal would have been (0, 0, 1), but is inverted!
+	And comps.normalv = vector(0, 0, -1)
+*/
+func TestPrepareComputationsInside(t *testing.T) {
+	r := Ray{NewPoint(0, 0, 0), NewVector(0, 0, 1)}
+	i := NewIntersection(1, NewSphere())
+	comps := PrepareComputations(i, r, Intersections{i})
+	assert.True(t, comps.Point.Equal(NewPoint(0, 0, 1)))
+	assert.True(t, comps.EyeV.Equal(NewVector(0, 0, -1)))
+	assert.True(t, comps.Inside)
+	assert.True(t, comps.NormalV.Equal(NewVector(0, 0, -1)))
+}
+
+/*
+	Scenario: The hit should offset the point
+	Given r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And shape ← sphere() with:
+		| transform | translation(0, 0, 1) |
+	And i ← intersection(5, shape)
+	When comps ← prepare_computations(i, r)
+	Then comps.over_point.z < -EPSILON/2
+	And comps.point.z > comps.over_point.z
+*/
+func TestPrepareComputationsOverPoint(t *testing.T) {
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	shape := NewSphere().WithTransform(NewTranslation(0, 0, 1))
+	i := NewIntersection(5, shape)
+	comps := PrepareComputations(i, r, Intersections{i})
+	assert.True(t, comps.OverPoint.Z < -epsilon/2)
+	assert.True(t, comps.Point.Z > comps.OverPoint.Z)
+}
+
+/*
+	Scenario: The under point is offset below the surface
+	Given r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And shape ← glass_sphere() with:
+		| transform | translation(0, 0, 1) |
+	And i ← intersection(5, shape)
+	And xs ← intersections(i)
+	When comps ← prepare_computations(i, r, xs)
+	Then comps.under_point.z > EPSILON/2
+	And comps.point.z < comps.under_point.z
+*/
+func TestPrepareComputationsUnderPoint(t *testing.T) {
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	shape := NewGlassSphere().WithTransform(NewTranslation(0, 0, 1))
+	i := NewIntersection(5, shape)
+	comps := PrepareComputations(i, r, Intersections{i})
+	assert.True(t, comps.UnderPoint.Z > epsilon/2)
+	assert.True(t, comps.Point.Z < comps.UnderPoint.Z)
+}
+
+/*
+	Scenario: Precomputing the reflection vector
+	Given shape ← plane()
+	And r ← ray(point(0, 1, -1), vector(0, -√2/2, √2/2))
+	And i ← intersection(√2, shape)
+	When comps ← prepare_computations(i, r)
+	Then comps.reflectv = vector(0, √2/2, √2/2)
+*/
+func TestPrepareComputationsReflectV(t *testing.T) {
+	shape := NewPlane()
+	r := Ray{NewPoint(0, 1, -1), NewVector(0, -math.Sqrt2/2, math.Sqrt2/2)}
+	i := NewIntersection(math.Sqrt2, shape)
+	comps := PrepareComputations(i, r, Intersections{i})
+	assert.True(t, comps.ReflectV.Equal(NewVector(0, math.Sqrt2/2, math.Sqrt2/2)))
+}
+
+/*
+	Scenario: Precomputing n1 and n2 as the ray leaves a glass sphere
+	Given shape ← glass_sphere()
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And xs ← intersections(4:shape, 6:shape)
+	When comps ← prepare_computations(xs[1], r, xs)
+	Then comps.n1 = 1.5
+	And comps.n2 = 1.0
+	And comps.inside = true
+*/
+func TestPrepareComputationsRefractiveIndices(t *testing.T) {
+	shape := NewGlassSphere()
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	xs := Intersections{NewIntersection(4, shape), NewIntersection(6, shape)}
+	comps := PrepareComputations(xs[1], r, xs)
+	assert.Equal(t, 1.5, comps.N1)
+	assert.Equal(t, 1.0, comps.N2)
+	assert.True(t, comps.Inside)
+}
+
+/*
+	Scenario: Preparing the normal on a smooth triangle
+	Given tri ← smooth_triangle(point(0, 1, 0), point(-1, 0, 0), point(1, 0, 0),
+		vector(0, 1, 0), vector(-1, 0, 0), vector(1, 0, 0))
+	And i ← intersection_with_uv(1, tri, 0.45, 0.25)
+	And r ← ray(point(-0.2, 0.3, -2), vector(0, 0, 1))
+	And xs ← intersections(i)
+	When comps ← prepare_computations(i, r, xs)
+	Then comps.normalv = vector(-0.5547, 0.83205, 0)
+*/
+func TestPrepareComputationsSmoothTriangle(t *testing.T) {
+	tri := NewSmoothTriangle(
+		NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0),
+		NewVector(0, 1, 0), NewVector(-1, 0, 0), NewVector(1, 0, 0),
+	)
+	i := NewIntersectionWithUV(1, tri, 0.45, 0.25)
+	r := Ray{NewPoint(-0.2, 0.3, -2), NewVector(0, 0, 1)}
+	comps := PrepareComputations(i, r, Intersections{i})
+	assert.True(t, comps.NormalV.Equal(NewVector(-0.5547, 0.83205, 0)), "%v", comps.NormalV)
+}
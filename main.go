@@ -1,7 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"os"
 )
 
 type projectile struct {
@@ -21,84 +24,66 @@ func tick(e environment, p projectile) projectile {
 }
 
 func main() {
-	// start the ray at z = -5
-	rayOrigin := NewPoint(0, 0, -5)
-	// put the wall at z = 10
-	wallZ := 10.0
-	wallSize := 10.0
 	canvasPixels := 400
 
-	w := canvasPixels
-	h := canvasPixels
-
-	pixelSize := wallSize / float64(canvasPixels)
-	half := wallSize / 2
-
-	canvas := NewCanvas(w, h)
-	// color := Red
-
 	shape1 := NewSphere().WithTransform(NewScaling(0.6, 0.6, 1).Translate(0.7, 0.7, 0))
-	shape1.Material.Color = Color{0.3, 0.4, 0.8}
+	shape1.Material().Color = Color{0.3, 0.4, 0.8}
 
 	shape2 := NewSphere().WithTransform(NewScaling(0.6, 0.6, 1).Translate(-0.5, -0.5, 0))
-	shape2.Material.Color = Color{0.8, 0.2, 0.3}
-
-	shapes := []Sphere{shape1, shape2}
+	shape2.Material().Color = Color{0.8, 0.2, 0.3}
 
-	// shape2
+	floor := NewPlane().WithTransform(NewTranslation(0, -1, 0))
+	floor.Material().Color = Color{1, 0.9, 0.9}
+	floor.Material().Specular = 0
 
 	// light source
 	lightPosition := NewPoint(-10, 10, -10)
 	lightColor := Color{1, 1, 1}
 	light := PointLight{lightPosition, lightColor}
 
-	i := 0
-	t := canvasPixels * canvasPixels
-	percentCount := 1
-	for y := 0; y < canvasPixels-1; y++ {
-		worldY := half - pixelSize*float64(y)
-		for x := 0; x < canvasPixels-1; x++ {
-			i += 1
-			if i%(t/100) == 0 {
-				fmt.Printf("\r%3d/100", percentCount)
-				percentCount += 1
-			}
-			worldX := -half + pixelSize*float64(x)
-
-			// describe point on the wall that the ray will target
-			position := NewPoint(worldX, worldY, wallZ)
-
-			r := Ray{rayOrigin, position.Sub(rayOrigin).Norm()}
-
-			for _, shape := range shapes {
-				xs, err := shape.Intersect(r)
-				if err != nil {
-					panic(err)
-				}
+	world := NewWorld()
+	world.Objects = []Shape{floor, shape1, shape2}
+	world.Lights = []PointLight{light}
+
+	// start the eye at z = -5, looking at the origin
+	camera := NewCamera(canvasPixels, canvasPixels, math.Pi/3).WithTransform(ViewTransform(
+		NewPoint(0, 0, -5),
+		NewPoint(0, 0, 0),
+		NewVector(0, 1, 0),
+	))
+
+	// a scene file given on the command line replaces the built in scene
+	if len(os.Args) > 1 {
+		scene, err := LoadScene(os.Args[1])
+		if err != nil {
+			panic(err.Error())
+		}
+		camera, world = scene.Camera, scene.World
+	}
 
-				if hit := xs.Hit(); hit != nil {
-					/*
-						point ← position(ray, hit.t)
-						normal ← normal_at(hit.object, point)
-						eye ← -ray.direction
-						color ← lighting(hit.object.material, light, point, eye, normal)
-					*/
-					point := r.Position(hit.T)
-					norm := hit.Object.NormalAt(point)
-					eye := r.Direction
-					color := hit.Object.Material.Lighting(light, point, eye, norm)
-					canvas.WritePixel(
-						x,
-						y,
-						color,
-					)
-				}
+	renderer := NewRenderer(camera, world)
+	progress := make(chan Progress)
+	renderer.Progress = progress
+	printed := make(chan struct{})
+	go func() {
+		defer close(printed)
+		reported := 0
+		for p := range progress {
+			if percent := int(p.Fraction() * 100); percent >= reported+10 {
+				reported = percent - percent%10
+				fmt.Printf("%v%%\n", reported)
 			}
 		}
+	}()
+
+	canvas, err := renderer.Render(context.Background())
+	close(progress)
+	<-printed
+	if err != nil {
+		panic(err.Error())
 	}
-	fmt.Print("\r100/100\r       \r")
 
-	err := canvas.ToPPM("bh.ppm")
+	err = canvas.Save("bh.png", DisplayOutput)
 	if err != nil {
 		panic(err.Error())
 	}
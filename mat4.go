@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Mat4 - 4x4 matrix held by value, indexed row/column like Matrix. This is
+// what transforms are made of, every ray and normal goes through one, so
+// none of its operations allocate. Matrix is still there for other sizes
+type Mat4 [4][4]float64
+
+func NewIdentityMat4() Mat4 {
+	return Mat4{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// NewMat4FromMatrix - copy a general matrix, which must be 4x4
+func NewMat4FromMatrix(m Matrix) (Mat4, error) {
+	if len(m) != 4 {
+		return Mat4{}, fmt.Errorf("expected a 4x4 matrix, got %v rows", len(m))
+	}
+	var a Mat4
+	for r := range m {
+		if len(m[r]) != 4 {
+			return Mat4{}, fmt.Errorf("expected a 4x4 matrix, row %v has %v columns", r, len(m[r]))
+		}
+		copy(a[r][:], m[r])
+	}
+	return a, nil
+}
+
+// Matrix - general matrix copy of a
+func (a Mat4) Matrix() Matrix {
+	m := NewSquareMatrix(4)
+	for r := range a {
+		copy(m[r], a[r][:])
+	}
+	return m
+}
+
+func (a Mat4) String() string {
+	return a.Matrix().String()
+}
+
+// Equal - whether every element is within epsilon of b
+func (a Mat4) Equal(b Mat4) bool {
+	for r := range a {
+		for c := range a[r] {
+			if math.Abs(a[r][c]-b[r][c]) >= epsilon {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MulM - multiply a matrix by another matrix
+func (a Mat4) MulM(b Mat4) Mat4 {
+	var result Mat4
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			result[r][c] = a[r][0]*b[0][c] +
+				a[r][1]*b[1][c] +
+				a[r][2]*b[2][c] +
+				a[r][3]*b[3][c]
+		}
+	}
+	return result
+}
+
+// MulT - multiply a matrix by a tuple
+func (a Mat4) MulT(t Tuple) Tuple {
+	return Tuple{
+		a[0][0]*t.X + a[0][1]*t.Y + a[0][2]*t.Z + a[0][3]*t.W,
+		a[1][0]*t.X + a[1][1]*t.Y + a[1][2]*t.Z + a[1][3]*t.W,
+		a[2][0]*t.X + a[2][1]*t.Y + a[2][2]*t.Z + a[2][3]*t.W,
+		a[3][0]*t.X + a[3][1]*t.Y + a[3][2]*t.Z + a[3][3]*t.W,
+	}
+}
+
+func (a Mat4) Transpose() Mat4 {
+	var t Mat4
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			t[r][c] = a[c][r]
+		}
+	}
+	return t
+}
+
+// minors2 - determinants of the 2x2 blocks in the top two rows (s) and the
+// bottom two rows (c), enough to expand both the determinant and the
+// inverse without recursing through submatrices
+func (a Mat4) minors2() (s, c [6]float64) {
+	s[0] = a[0][0]*a[1][1] - a[1][0]*a[0][1]
+	s[1] = a[0][0]*a[1][2] - a[1][0]*a[0][2]
+	s[2] = a[0][0]*a[1][3] - a[1][0]*a[0][3]
+	s[3] = a[0][1]*a[1][2] - a[1][1]*a[0][2]
+	s[4] = a[0][1]*a[1][3] - a[1][1]*a[0][3]
+	s[5] = a[0][2]*a[1][3] - a[1][2]*a[0][3]
+
+	c[5] = a[2][2]*a[3][3] - a[3][2]*a[2][3]
+	c[4] = a[2][1]*a[3][3] - a[3][1]*a[2][3]
+	c[3] = a[2][1]*a[3][2] - a[3][1]*a[2][2]
+	c[2] = a[2][0]*a[3][3] - a[3][0]*a[2][3]
+	c[1] = a[2][0]*a[3][2] - a[3][0]*a[2][2]
+	c[0] = a[2][0]*a[3][1] - a[3][0]*a[2][1]
+	return s, c
+}
+
+func (a Mat4) Determinant() float64 {
+	s, c := a.minors2()
+	return s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+}
+
+func (a Mat4) Invertible() bool {
+	return a.Determinant() != 0
+}
+
+func (a Mat4) Inverse() (Mat4, error) {
+	s, c := a.minors2()
+	det := s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+	if det == 0 {
+		return Mat4{}, fmt.Errorf("cannot invert non-invertible matrix")
+	}
+	inv := 1 / det
+
+	var b Mat4
+	b[0][0] = (a[1][1]*c[5] - a[1][2]*c[4] + a[1][3]*c[3]) * inv
+	b[0][1] = (-a[0][1]*c[5] + a[0][2]*c[4] - a[0][3]*c[3]) * inv
+	b[0][2] = (a[3][1]*s[5] - a[3][2]*s[4] + a[3][3]*s[3]) * inv
+	b[0][3] = (-a[2][1]*s[5] + a[2][2]*s[4] - a[2][3]*s[3]) * inv
+
+	b[1][0] = (-a[1][0]*c[5] + a[1][2]*c[2] - a[1][3]*c[1]) * inv
+	b[1][1] = (a[0][0]*c[5] - a[0][2]*c[2] + a[0][3]*c[1]) * inv
+	b[1][2] = (-a[3][0]*s[5] + a[3][2]*s[2] - a[3][3]*s[1]) * inv
+	b[1][3] = (a[2][0]*s[5] - a[2][2]*s[2] + a[2][3]*s[1]) * inv
+
+	b[2][0] = (a[1][0]*c[4] - a[1][1]*c[2] + a[1][3]*c[0]) * inv
+	b[2][1] = (-a[0][0]*c[4] + a[0][1]*c[2] - a[0][3]*c[0]) * inv
+	b[2][2] = (a[3][0]*s[4] - a[3][1]*s[2] + a[3][3]*s[0]) * inv
+	b[2][3] = (-a[2][0]*s[4] + a[2][1]*s[2] - a[2][3]*s[0]) * inv
+
+	b[3][0] = (-a[1][0]*c[3] + a[1][1]*c[1] - a[1][2]*c[0]) * inv
+	b[3][1] = (a[0][0]*c[3] - a[0][1]*c[1] + a[0][2]*c[0]) * inv
+	b[3][2] = (-a[3][0]*s[3] + a[3][1]*s[1] - a[3][2]*s[0]) * inv
+	b[3][3] = (a[2][0]*s[3] - a[2][1]*s[1] + a[2][2]*s[0]) * inv
+
+	return b, nil
+}
+
+func (a Mat4) MustInverse() Mat4 {
+	I, err := a.Inverse()
+	if err != nil {
+		panic(err)
+	}
+	return I
+}
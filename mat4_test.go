@@ -0,0 +1,289 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario: Multiplying two 4x4 matrices
+	Given the following matrix A:
+		| 1 | 2 | 3 | 4 |
+		| 5 | 6 | 7 | 8 |
+		| 9 | 8 | 7 | 6 |
+		| 5 | 4 | 3 | 2 |
+	And the following matrix B:
+		| -2 | 1 | 2 | 3  |
+		| 3  | 2 | 1 | -1 |
+		| 4  | 3 | 6 | 5  |
+		| 1  | 2 | 7 | 8  |
+	Then A * B is the following 4x4 matrix:
+		| 20 | 22 | 50  | 48  |
+		| 44 | 54 | 114 | 108 |
+		| 40 | 58 | 110 | 102 |
+		| 16 | 26 | 46  | 42  |
+*/
+func TestMat4MulM(t *testing.T) {
+	A := Mat4{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 8, 7, 6},
+		{5, 4, 3, 2},
+	}
+	B := Mat4{
+		{-2, 1, 2, 3},
+		{3, 2, 1, -1},
+		{4, 3, 6, 5},
+		{1, 2, 7, 8},
+	}
+	expected := Mat4{
+		{20, 22, 50, 48},
+		{44, 54, 114, 108},
+		{40, 58, 110, 102},
+		{16, 26, 46, 42},
+	}
+	assert.Equal(t, expected, A.MulM(B))
+}
+
+/*
+	Scenario: A 4x4 matrix multiplied by a tuple
+	Given the following matrix A:
+		| 1 | 2 | 3 | 4 |
+		| 2 | 4 | 4 | 2 |
+		| 8 | 6 | 4 | 1 |
+		| 0 | 0 | 0 | 1 |
+	And b ← tuple(1, 2, 3, 1)
+	Then A * b = tuple(18, 24, 33, 1)
+*/
+func TestMat4MulT(t *testing.T) {
+	A := Mat4{
+		{1, 2, 3, 4},
+		{2, 4, 4, 2},
+		{8, 6, 4, 1},
+		{0, 0, 0, 1},
+	}
+	b := Tuple{1, 2, 3, 1}
+	assert.Equal(t, Tuple{18, 24, 33, 1}, A.MulT(b))
+}
+
+/*
+	Scenario: Multiplying a 4x4 matrix by the identity matrix
+	Given the following matrix A:
+		| 0 | 1 | 2  | 4  |
+		| 1 | 2 | 4  | 8  |
+		| 2 | 4 | 8  | 16 |
+		| 4 | 8 | 16 | 32 |
+	Then A * identity_matrix = A
+*/
+func TestMat4MulIdentity(t *testing.T) {
+	A := Mat4{
+		{0, 1, 2, 4},
+		{1, 2, 4, 8},
+		{2, 4, 8, 16},
+		{4, 8, 16, 32},
+	}
+	assert.Equal(t, A, A.MulM(NewIdentityMat4()))
+}
+
+/*
+	Scenario: Transposing a 4x4 matrix
+	Given the following matrix A:
+		| 0 | 9 | 3 | 0 |
+		| 9 | 8 | 0 | 8 |
+		| 1 | 8 | 5 | 3 |
+		| 0 | 0 | 5 | 8 |
+	Then transpose(A) is the following matrix:
+		| 0 | 9 | 1 | 0 |
+		| 9 | 8 | 8 | 0 |
+		| 3 | 0 | 5 | 5 |
+		| 0 | 8 | 3 | 8 |
+*/
+func TestMat4Transpose(t *testing.T) {
+	A := Mat4{
+		{0, 9, 3, 0},
+		{9, 8, 0, 8},
+		{1, 8, 5, 3},
+		{0, 0, 5, 8},
+	}
+	expected := Mat4{
+		{0, 9, 1, 0},
+		{9, 8, 8, 0},
+		{3, 0, 5, 5},
+		{0, 8, 3, 8},
+	}
+	assert.Equal(t, expected, A.Transpose())
+	assert.Equal(t, NewIdentityMat4(), NewIdentityMat4().Transpose())
+}
+
+/*
+	Scenario: Calculating the determinant of a 4x4 matrix
+	Given the following 4x4 matrix A:
+		| -2 | -8 | 3  | 5  |
+		| -3 | 1  | 7  | 3  |
+		| 1  | 2  | -9 | 6  |
+		| -6 | 7  | 7  | -9 |
+	Then determinant(A) = -4071
+*/
+func TestMat4Determinant(t *testing.T) {
+	A := Mat4{
+		{-2, -8, 3, 5},
+		{-3, 1, 7, 3},
+		{1, 2, -9, 6},
+		{-6, 7, 7, -9},
+	}
+	assert.Equal(t, -4071.0, A.Determinant())
+	assert.True(t, A.Invertible())
+}
+
+/*
+	Scenario: Testing a noninvertible 4x4 matrix for invertibility
+	Given the following 4x4 matrix A:
+		| -4 | 2  | -2 | -3 |
+		| 9  | 6  | 2  | 6  |
+		| 0  | -5 | 1  | -5 |
+		| 0  | 0  | 0  | 0  |
+	Then determinant(A) = 0
+	And A is not invertible
+	And inverse(A) is an error
+*/
+func TestMat4NonInvertible(t *testing.T) {
+	A := Mat4{
+		{-4, 2, -2, -3},
+		{9, 6, 2, 6},
+		{0, -5, 1, -5},
+		{0, 0, 0, 0},
+	}
+	assert.Equal(t, 0.0, A.Determinant())
+	assert.False(t, A.Invertible())
+	_, err := A.Inverse()
+	assert.NotNil(t, err)
+}
+
+/*
+	Scenario Outline: The closed form inverse agrees with the general matrix
+	Given A ← <matrix>
+	Then inverse(A) = inverse(matrix(A))
+
+	Examples:
+		| matrix                                                       |
+		| [-5 2 6 -8] [1 -5 1 8] [7 7 -6 -7] [1 -3 7 4]                |
+		| [8 -5 9 2] [7 5 6 1] [-6 0 9 6] [-3 0 -9 -4]                 |
+		| [9 3 0 9] [-5 -2 -6 -3] [-4 9 6 4] [-7 6 6 2]                |
+		| translation(1, 2, 3) * rotation_y(π / 5) * scaling(2, 3, 4)  |
+*/
+func TestMat4InverseMatchesMatrix(t *testing.T) {
+	examples := []Mat4{
+		{{-5, 2, 6, -8}, {1, -5, 1, 8}, {7, 7, -6, -7}, {1, -3, 7, 4}},
+		{{8, -5, 9, 2}, {7, 5, 6, 1}, {-6, 0, 9, 6}, {-3, 0, -9, -4}},
+		{{9, 3, 0, 9}, {-5, -2, -6, -3}, {-4, 9, 6, 4}, {-7, 6, 6, 2}},
+		NewScaling(2, 3, 4).RotateY(math.Pi/5).Translate(1, 2, 3),
+	}
+	for _, A := range examples {
+		inv, err := A.Inverse()
+		require.Nil(t, err)
+		expected := A.Matrix().MustInverse()
+		for i := range expected {
+			assert.InDeltaSlice(t, expected[i], inv[i][:], 0.00001)
+		}
+	}
+}
+
+/*
+	Scenario: Multiplying a 4x4 product by its inverse
+	Given the following 4x4 matrix A:
+		| 3  | -9 | 7  | 3  |
+		| 3  | -8 | 2  | -9 |
+		| -4 | 4  | 4  | 1  |
+		| -6 | 5  | -1 | 1  |
+	And the following 4x4 matrix B:
+		| 8 | 2  | 2 | 2 |
+		| 3 | -1 | 7 | 0 |
+		| 7 | 0  | 5 | 4 |
+		| 6 | -2 | 0 | 5 |
+	And C ← A * B
+	Then C * inverse(B) = A
+*/
+func TestMat4ProductMultiplyItsInverse(t *testing.T) {
+	A := Mat4{
+		{3, -9, 7, 3},
+		{3, -8, 2, -9},
+		{-4, 4, 4, 1},
+		{-6, 5, -1, 1},
+	}
+	B := Mat4{
+		{8, 2, 2, 2},
+		{3, -1, 7, 0},
+		{7, 0, 5, 4},
+		{6, -2, 0, 5},
+	}
+	C := A.MulM(B)
+	assert.True(t, A.Equal(C.MulM(B.MustInverse())))
+}
+
+/*
+	Scenario: Converting between 4x4 and general matrices
+	Given A ← translation(1, 2, 3)
+	Then mat4(matrix(A)) = A
+	And mat4 of a 3x3 matrix is an error
+*/
+func TestMat4FromMatrix(t *testing.T) {
+	A := NewTranslation(1, 2, 3)
+	B, err := NewMat4FromMatrix(A.Matrix())
+	require.Nil(t, err)
+	assert.Equal(t, A, B)
+
+	_, err = NewMat4FromMatrix(NewIdentityMatrix(3))
+	assert.NotNil(t, err)
+}
+
+/*
+	Scenario: 4x4 matrix operations do not allocate
+	Given A ← translation(1, 2, 3) * rotation_x(π / 3)
+	And r ← ray(point(1, 2, 3), vector(0, 0, 1))
+	Then A * A, A * point, inverse(A), transpose(A) and transform(r, A) allocate nothing
+*/
+func TestMat4NoAllocations(t *testing.T) {
+	A := NewRotationX(math.Pi/3).Translate(1, 2, 3)
+	r := Ray{NewPoint(1, 2, 3), NewVector(0, 0, 1)}
+	allocs := testing.AllocsPerRun(100, func() {
+		B := A.MulM(A)
+		B.MulT(NewPoint(1, 2, 3))
+		B, _ = A.Inverse()
+		B = B.Transpose()
+		r.Transform(B)
+	})
+	assert.Equal(t, 0.0, allocs)
+}
+
+func BenchmarkMatrixInverse(b *testing.B) {
+	A := NewScaling(2, 3, 4).RotateY(math.Pi/5).Translate(1, 2, 3).Matrix()
+	for i := 0; i < b.N; i++ {
+		A.MustInverse()
+	}
+}
+
+func BenchmarkMat4Inverse(b *testing.B) {
+	A := NewScaling(2, 3, 4).RotateY(math.Pi/5).Translate(1, 2, 3)
+	for i := 0; i < b.N; i++ {
+		A.MustInverse()
+	}
+}
+
+func BenchmarkMatrixMulT(b *testing.B) {
+	A := NewScaling(2, 3, 4).RotateY(math.Pi/5).Translate(1, 2, 3).Matrix()
+	p := NewPoint(1, 2, 3)
+	for i := 0; i < b.N; i++ {
+		A.MustMulT(p)
+	}
+}
+
+func BenchmarkMat4MulT(b *testing.B) {
+	A := NewScaling(2, 3, 4).RotateY(math.Pi/5).Translate(1, 2, 3)
+	p := NewPoint(1, 2, 3)
+	for i := 0; i < b.N; i++ {
+		A.MulT(p)
+	}
+}
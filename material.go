@@ -5,20 +5,39 @@ import (
 )
 
 type Material struct {
-	Color     Color
+	Color Color
+	// Pattern - when set, replaces Color with the pattern's color at each point
+	Pattern   Pattern
 	Ambient   float64
 	Diffuse   float64
 	Specular  float64
 	Shininess float64
+	// Reflective - fraction of light mirrored off the surface, 0 for matte to 1 for a perfect mirror
+	Reflective float64
+	// Transparency - fraction of light let through the surface, 0 for opaque
+	Transparency float64
+	// RefractiveIndex - how much light bends entering the material, see the
+	// Refractive constants for common ones
+	RefractiveIndex float64
 }
 
+// Refractive indices of common materials
+const (
+	RefractiveVacuum  = 1.0
+	RefractiveAir     = 1.00029
+	RefractiveWater   = 1.333
+	RefractiveGlass   = 1.5
+	RefractiveDiamond = 2.417
+)
+
 func NewMaterial() Material {
 	return Material{
-		Color:     Color{1, 1, 1},
-		Ambient:   0.1,
-		Diffuse:   0.9,
-		Specular:  0.9,
-		Shininess: 200,
+		Color:           Color{1, 1, 1},
+		Ambient:         0.1,
+		Diffuse:         0.9,
+		Specular:        0.9,
+		Shininess:       200,
+		RefractiveIndex: RefractiveVacuum,
 	}
 }
 
@@ -26,15 +45,25 @@ func reflect(l, n Tuple) Tuple {
 	return l.Reflect(n)
 }
 
-func (m Material) Lighting(light PointLight, position, eyev, normv Tuple) Color {
+// Lighting - phong shading of a point on the surface of object, inShadow leaves
+// only the ambient term. object is only used to place the material's pattern
+func (m Material) Lighting(object Shape, light PointLight, position, eyev, normv Tuple, inShadow bool) Color {
+	color := m.Color
+	if m.Pattern != nil {
+		color = PatternAtShape(m.Pattern, object, position)
+	}
+
 	// combine surface color with light's color/intensity
-	effectiveColor := m.Color.MulC(light.Intensity)
+	effectiveColor := color.MulC(light.Intensity)
 
 	// find direction to the light source
 	lightv := light.Position.Sub(position).Norm()
 
 	// compute ambient contribution
 	ambient := effectiveColor.MulS(m.Ambient)
+	if inShadow {
+		return ambient
+	}
 	var diffuse, specular Color
 
 	// lightDotNorm is cosine of angle btn lightv and normv
@@ -31,6 +31,7 @@ func TestDefaultMaterial(t *testing.T) {
 
 	Given m ← material()
 	And position ← point(0, 0, 0)
+	And object ← sphere()
 */
 
 /*
@@ -38,7 +39,7 @@ func TestDefaultMaterial(t *testing.T) {
 	Given eyev ← vector(0, 0, -1)
 	And normalv ← vector(0, 0, -1)
 	And light ← point_light(point(0, 0, -10), color(1, 1, 1))
-	When result ← lighting(m, light, position, eyev, normalv)
+	When result ← lighting(m, object, light, position, eyev, normalv)
 	Then result = color(1.9, 1.9, 1.9)
 */
 func LightingEyeBetweenLightSurface(t *testing.T) {
@@ -47,7 +48,7 @@ func LightingEyeBetweenLightSurface(t *testing.T) {
 	eyev := NewVector(0, 0, -1)
 	normv := NewVector(0, 0, -1)
 	light := PointLight{NewPoint(0, 0, -1), Color{1, 1, 1}}
-	result := m.Lighting(light, position, eyev, normv)
+	result := m.Lighting(NewSphere(), light, position, eyev, normv, false)
 	assert.True(t, result.Equal(Color{1.9, 1.9, 1.9}))
 }
 
@@ -56,7 +57,7 @@ func LightingEyeBetweenLightSurface(t *testing.T) {
 	Given eyev ← vector(0, √2/2, -√2/2)
 	And normalv ← vector(0, 0, -1)
 	And light ← point_light(point(0, 0, -10), color(1, 1, 1))
-	When result ← lighting(m, light, position, eyev, normalv)
+	When result ← lighting(m, object, light, position, eyev, normalv)
 	Then result = color(1.0, 1.0, 1.0)
 */
 func LightingEyeBetweenLightSurfaceEyeOffset45(t *testing.T) {
@@ -65,7 +66,7 @@ func LightingEyeBetweenLightSurfaceEyeOffset45(t *testing.T) {
 	eyev := NewVector(0, math.Sqrt2/2, -math.Sqrt2/2)
 	normv := NewVector(0, 0, -1)
 	light := PointLight{NewPoint(0, 0, -10), Color{1, 1, 1}}
-	result := m.Lighting(light, position, eyev, normv)
+	result := m.Lighting(NewSphere(), light, position, eyev, normv, false)
 	assert.True(t, Color{1, 1, 1}.Equal(result))
 }
 
@@ -74,7 +75,7 @@ func LightingEyeBetweenLightSurfaceEyeOffset45(t *testing.T) {
 	Given eyev ← vector(0, 0, -1)
 	And normalv ← vector(0, 0, -1)
 	And light ← point_light(point(0, 10, -10), color(1, 1, 1))
-	When result ← lighting(m, light, position, eyev, normalv)
+	When result ← lighting(m, object, light, position, eyev, normalv)
 	Then result = color(0.7364, 0.7364, 0.7364)
 */
 func LightingEyeOppositeSurphaseLightOffset45(t *testing.T) {
@@ -83,7 +84,7 @@ func LightingEyeOppositeSurphaseLightOffset45(t *testing.T) {
 	eyev := NewVector(0, 0, -1)
 	normv := NewVector(0, 0, -1)
 	light := PointLight{NewPoint(0, 10, -10), Color{1, 1, 1}}
-	result := m.Lighting(light, position, eyev, normv)
+	result := m.Lighting(NewSphere(), light, position, eyev, normv, false)
 	assert.True(t, Color{0.7364, 0.7364, 0.7364}.Equal(result))
 }
 
@@ -92,7 +93,7 @@ func LightingEyeOppositeSurphaseLightOffset45(t *testing.T) {
 	Given eyev ← vector(0, -√2/2, -√2/2)
 	And normalv ← vector(0, 0, -1)
 	And light ← point_light(point(0, 10, -10), color(1, 1, 1))
-	When result ← lighting(m, light, position, eyev, normalv)
+	When result ← lighting(m, object, light, position, eyev, normalv)
 	Then result = color(1.6364, 1.6364, 1.6364)
 */
 func TestLightingEyeInPathReflectionVector(t *testing.T) {
@@ -101,7 +102,7 @@ func TestLightingEyeInPathReflectionVector(t *testing.T) {
 	eyev := NewVector(0, -math.Sqrt2/2, -math.Sqrt2/2)
 	normv := NewVector(0, 0, -1)
 	light := PointLight{NewPoint(0, 10, -10), Color{1, 1, 1}}
-	result := m.Lighting(light, position, eyev, normv)
+	result := m.Lighting(NewSphere(), light, position, eyev, normv, false)
 	fmt.Println(result)
 	assert.True(t, Color{1.6364, 1.6364, 1.6364}.Equal(result))
 }
@@ -111,7 +112,7 @@ func TestLightingEyeInPathReflectionVector(t *testing.T) {
 	Given eyev ← vector(0, 0, -1)
 	And normalv ← vector(0, 0, -1)
 	And light ← point_light(point(0, 0, 10), color(1, 1, 1))
-	When result ← lighting(m, light, position, eyev, normalv)
+	When result ← lighting(m, object, light, position, eyev, normalv)
 	Then result = color(0.1, 0.1, 0.1)
 */
 func TestLightingLightBehindSurface(t *testing.T) {
@@ -120,7 +121,100 @@ func TestLightingLightBehindSurface(t *testing.T) {
 	eyev := NewVector(0, 0, -1)
 	normv := NewVector(0, 0, -1)
 	light := PointLight{NewPoint(0, 0, 10), Color{1, 1, 1}}
-	result := m.Lighting(light, position, eyev, normv)
+	result := m.Lighting(NewSphere(), light, position, eyev, normv, false)
 	fmt.Println(result)
 	assert.True(t, Color{0.1, 0.1, 0.1}.Equal(result))
 }
+
+/*
+	Scenario: Lighting with the surface in shadow
+	Given eyev ← vector(0, 0, -1)
+	And normalv ← vector(0, 0, -1)
+	And light ← point_light(point(0, 0, -10), color(1, 1, 1))
+	And in_shadow ← true
+	When result ← lighting(m, object, light, position, eyev, normalv, in_shadow)
+	Then result = color(0.1, 0.1, 0.1)
+*/
+func TestLightingSurfaceInShadow(t *testing.T) {
+	m := NewMaterial()
+	position := NewPoint(0, 0, 0)
+	eyev := NewVector(0, 0, -1)
+	normv := NewVector(0, 0, -1)
+	light := PointLight{NewPoint(0, 0, -10), Color{1, 1, 1}}
+	result := m.Lighting(NewSphere(), light, position, eyev, normv, true)
+	assert.True(t, Color{0.1, 0.1, 0.1}.Equal(result))
+}
+
+/*
+	Scenario: Lighting with a pattern applied
+	Given m.pattern ← stripe_pattern(color(1, 1, 1), color(0, 0, 0))
+	And m.ambient ← 1
+	And m.diffuse ← 0
+	And m.specular ← 0
+	And eyev ← vector(0, 0, -1)
+	And normalv ← vector(0, 0, -1)
+	And light ← point_light(point(0, 0, -10), color(1, 1, 1))
+	When c1 ← lighting(m, object, light, point(0.9, 0, 0), eyev, normalv, false)
+	And c2 ← lighting(m, object, light, point(1.1, 0, 0), eyev, normalv, false)
+	Then c1 = color(1, 1, 1)
+	And c2 = color(0, 0, 0)
+*/
+func TestLightingWithPattern(t *testing.T) {
+	m := NewMaterial()
+	m.Pattern = NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black))
+	m.Ambient = 1
+	m.Diffuse = 0
+	m.Specular = 0
+	object := NewSphere()
+	eyev := NewVector(0, 0, -1)
+	normv := NewVector(0, 0, -1)
+	light := PointLight{NewPoint(0, 0, -10), Color{1, 1, 1}}
+	c1 := m.Lighting(object, light, NewPoint(0.9, 0, 0), eyev, normv, false)
+	c2 := m.Lighting(object, light, NewPoint(1.1, 0, 0), eyev, normv, false)
+	assert.True(t, c1.Equal(White))
+	assert.True(t, c2.Equal(Black))
+}
+
+/*
+	Scenario: The pattern is sampled in the object's space
+	Given object ← sphere() with transform scaling(2, 2, 2)
+	And m.pattern ← stripe_pattern(color(1, 1, 1), color(0, 0, 0))
+	And m.ambient ← 1
+	And m.diffuse ← 0
+	And m.specular ← 0
+	When c ← lighting(m, object, light, point(1.5, 0, 0), eyev, normalv, false)
+	Then c = color(1, 1, 1)
+*/
+func TestLightingPatternObjectSpace(t *testing.T) {
+	m := NewMaterial()
+	m.Pattern = NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black))
+	m.Ambient = 1
+	m.Diffuse = 0
+	m.Specular = 0
+	object := NewSphere().WithTransform(NewScaling(2, 2, 2))
+	light := PointLight{NewPoint(0, 0, -10), Color{1, 1, 1}}
+	c := m.Lighting(object, light, NewPoint(1.5, 0, 0), NewVector(0, 0, -1), NewVector(0, 0, -1), false)
+	assert.True(t, c.Equal(White))
+}
+
+/*
+	Scenario: Reflectivity for the default material
+	Given m ← material()
+	Then m.reflective = 0.0
+*/
+func TestDefaultMaterialReflective(t *testing.T) {
+	m := NewMaterial()
+	assert.Equal(t, 0.0, m.Reflective)
+}
+
+/*
+	Scenario: Transparency and Refractive Index for the default material
+	Given m ← material()
+	Then m.transparency = 0.0
+	And m.refractive_index = 1.0
+*/
+func TestDefaultMaterialTransparency(t *testing.T) {
+	m := NewMaterial()
+	assert.Equal(t, 0.0, m.Transparency)
+	assert.Equal(t, 1.0, m.RefractiveIndex)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+	Scenario: Noise is zero at whole numbered points
+	Then perlin_noise(0, 0, 0) = 0
+	And perlin_noise(1, 2, 3) = 0
+	And perlin_noise(-4, 7, -300) = 0
+*/
+func TestPerlinNoiseZeroAtLattice(t *testing.T) {
+	assert.Equal(t, 0.0, PerlinNoise(0, 0, 0))
+	assert.Equal(t, 0.0, PerlinNoise(1, 2, 3))
+	assert.Equal(t, 0.0, PerlinNoise(-4, 7, -300))
+}
+
+/*
+	Scenario: Noise matches the reference implementation
+	Then perlin_noise(3.14, 42, 7) = 0.13691
+	And perlin_noise(0.5, 0.5, 0.5) = -0.25
+*/
+func TestPerlinNoiseReference(t *testing.T) {
+	assert.InDelta(t, 0.13691, PerlinNoise(3.14, 42, 7), 0.00001)
+	assert.InDelta(t, -0.25, PerlinNoise(0.5, 0.5, 0.5), 0.00001)
+}
+
+/*
+	Scenario: Noise stays between -1 and 1, repeats every 256 units and is smooth
+	Given points across several units
+	Then -1 ≤ perlin_noise(p) ≤ 1
+	And perlin_noise(p) = perlin_noise(p + (256, 256, 256))
+	And perlin_noise(p) is close to perlin_noise(p + (0.001, 0, 0))
+*/
+func TestPerlinNoiseRange(t *testing.T) {
+	for x := -3.0; x < 3; x += 0.23 {
+		for y := -3.0; y < 3; y += 0.31 {
+			for z := -3.0; z < 3; z += 0.47 {
+				n := PerlinNoise(x, y, z)
+				assert.True(t, n >= -1 && n <= 1, "%v", n)
+				assert.InDelta(t, n, PerlinNoise(x+256, y+256, z+256), 0.00001)
+				assert.InDelta(t, n, PerlinNoise(x+0.001, y, z), 0.01)
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ObjFile - geometry read from a wavefront obj file. Vertices, normals and
+// texture coordinates are kept in file order, so the obj index i is at i-1
+type ObjFile struct {
+	Vertices []Tuple
+	Normals  []Tuple
+	Textures []Tuple
+
+	// DefaultGroup - faces that appear before any g or o statement
+	DefaultGroup *Group
+	// Groups - every o and g statement, keyed by name
+	Groups map[string]*Group
+	// IgnoredLines - line numbers of statements the parser does not understand
+	IgnoredLines []int
+
+	// root holds the default group and every top level o/g group
+	root *Group
+}
+
+// LoadObj - parse the obj file with the given name
+func LoadObj(fn string) (*ObjFile, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	obj, err := ParseObj(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", fn, err)
+	}
+	return obj, nil
+}
+
+// ParseObj - parse obj statements from r. Polygons are fan triangulated,
+// faces with vertex normals become smooth triangles
+func ParseObj(r io.Reader) (*ObjFile, error) {
+	obj := &ObjFile{
+		DefaultGroup: NewGroup(),
+		Groups:       map[string]*Group{},
+		root:         NewGroup(),
+	}
+	obj.root.AddChild(obj.DefaultGroup)
+
+	// o starts a new object under the root, g starts a group inside the current object
+	var object, current *Group
+	current = obj.DefaultGroup
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		var err error
+		switch fields[0] {
+		case "v":
+			var v Tuple
+			v, err = parseObjTuple(fields[1:], 3)
+			v.W = 1
+			obj.Vertices = append(obj.Vertices, v)
+		case "vn":
+			var n Tuple
+			n, err = parseObjTuple(fields[1:], 3)
+			obj.Normals = append(obj.Normals, n)
+		case "vt":
+			var t Tuple
+			t, err = parseObjTuple(fields[1:], 1)
+			obj.Textures = append(obj.Textures, t)
+		case "f":
+			var triangles []Shape
+			triangles, err = obj.parseFace(fields[1:])
+			current.AddChild(triangles...)
+		case "o", "g":
+			if len(fields) < 2 {
+				err = fmt.Errorf("%v statement without a name", fields[0])
+				break
+			}
+			name := strings.Join(fields[1:], " ")
+			g, ok := obj.Groups[name]
+			if !ok {
+				g = NewGroup()
+				obj.Groups[name] = g
+				if fields[0] == "g" && object != nil {
+					object.AddChild(g)
+				} else {
+					obj.root.AddChild(g)
+				}
+			}
+			if fields[0] == "o" {
+				object = g
+			}
+			current = g
+		default:
+			obj.IgnoredLines = append(obj.IgnoredLines, line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %v", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %v: %v", line+1, err)
+	}
+
+	return obj, nil
+}
+
+// parseObjTuple - read min to 4 numbers, keeping the first 3 as x, y, z.
+// vertices may carry an optional w, textures an optional v and w
+func parseObjTuple(fields []string, min int) (Tuple, error) {
+	if len(fields) < min || len(fields) > 4 {
+		return Tuple{}, fmt.Errorf("expected %v to 4 numbers, got %v", min, len(fields))
+	}
+	xyz := [3]float64{}
+	for i := 0; i < len(fields) && i < 3; i++ {
+		f, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return Tuple{}, fmt.Errorf("invalid number %q", fields[i])
+		}
+		xyz[i] = f
+	}
+	return Tuple{xyz[0], xyz[1], xyz[2], 0}, nil
+}
+
+// objIndex - convert a 1 based (or negative, relative to the end) obj index into a slice index
+func objIndex(field string, count int, kind string) (int, error) {
+	i, err := strconv.Atoi(field)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v index %q", kind, field)
+	}
+	if i < 0 {
+		i = count + i + 1
+	}
+	if i < 1 || i > count {
+		return 0, fmt.Errorf("%v index %v out of range, have %v", kind, field, count)
+	}
+	return i - 1, nil
+}
+
+// parseFace - triangles for a face of the form v, v/vt, v//vn or v/vt/vn
+func (obj *ObjFile) parseFace(fields []string) ([]Shape, error) {
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("face needs at least 3 vertices, got %v", len(fields))
+	}
+
+	vertices := make([]Tuple, len(fields))
+	normals := make([]Tuple, 0, len(fields))
+	for i, field := range fields {
+		parts := strings.Split(field, "/")
+		if len(parts) > 3 {
+			return nil, fmt.Errorf("invalid face vertex %q", field)
+		}
+
+		v, err := objIndex(parts[0], len(obj.Vertices), "vertex")
+		if err != nil {
+			return nil, err
+		}
+		vertices[i] = obj.Vertices[v]
+
+		if len(parts) > 1 && parts[1] != "" {
+			if _, err := objIndex(parts[1], len(obj.Textures), "texture"); err != nil {
+				return nil, err
+			}
+		}
+		if len(parts) > 2 && parts[2] != "" {
+			n, err := objIndex(parts[2], len(obj.Normals), "normal")
+			if err != nil {
+				return nil, err
+			}
+			normals = append(normals, obj.Normals[n])
+		}
+	}
+	if len(normals) != 0 && len(normals) != len(vertices) {
+		return nil, fmt.Errorf("face gives normals for only some of its vertices")
+	}
+
+	// fan triangulation around the first vertex, assumes a convex polygon
+	triangles := make([]Shape, 0, len(vertices)-2)
+	for i := 1; i < len(vertices)-1; i++ {
+		if len(normals) > 0 {
+			triangles = append(triangles, NewSmoothTriangle(
+				vertices[0], vertices[i], vertices[i+1],
+				normals[0], normals[i], normals[i+1],
+			))
+		} else {
+			triangles = append(triangles, NewTriangle(vertices[0], vertices[i], vertices[i+1]))
+		}
+	}
+	return triangles, nil
+}
+
+// ToGroup - everything in the file as a single group, ready to add to a world
+func (obj *ObjFile) ToGroup() *Group {
+	return obj.root
+}
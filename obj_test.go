@@ -0,0 +1,452 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario: Ignoring unrecognized lines
+	Given gibberish ← a file containing:
+		"""
+		There was a young lady named Bright
+		who traveled much faster than light.
+		She set out one day
+		in a relative way,
+		and came back the previous night.
+		"""
+	When parser ← parse_obj_file(gibberish)
+	Then parser should have ignored 5 lines
+*/
+func TestObjIgnoreUnrecognizedLines(t *testing.T) {
+	gibberish := `There was a young lady named Bright
+who traveled much faster than light.
+She set out one day
+in a relative way,
+and came back the previous night.`
+	obj, err := ParseObj(strings.NewReader(gibberish))
+	require.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, obj.IgnoredLines)
+}
+
+/*
+	Scenario: Vertex records
+	Given file ← a file containing:
+		"""
+		v -1 1 0
+		v -1.0000 0.5000 0.0000
+		v 1 0 0
+		v 1 1 0
+		"""
+	When parser ← parse_obj_file(file)
+	Then parser.vertices[1] = point(-1, 1, 0)
+	And parser.vertices[2] = point(-1, 0.5, 0)
+	And parser.vertices[3] = point(1, 0, 0)
+	And parser.vertices[4] = point(1, 1, 0)
+*/
+func TestObjVertexRecords(t *testing.T) {
+	file := `v -1 1 0
+v -1.0000 0.5000 0.0000
+v 1 0 0
+v 1 1 0`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.Nil(t, err)
+	require.Equal(t, 4, len(obj.Vertices))
+	assert.Equal(t, NewPoint(-1, 1, 0), obj.Vertices[0])
+	assert.Equal(t, NewPoint(-1, 0.5, 0), obj.Vertices[1])
+	assert.Equal(t, NewPoint(1, 0, 0), obj.Vertices[2])
+	assert.Equal(t, NewPoint(1, 1, 0), obj.Vertices[3])
+}
+
+/*
+	Scenario: Parsing triangle faces
+	Given file ← a file containing:
+		"""
+		v -1 1 0
+		v -1 0 0
+		v 1 0 0
+		v 1 1 0
+
+		f 1 2 3
+		f 1 3 4
+		"""
+	When parser ← parse_obj_file(file)
+	And g ← parser.default_group
+	And t1 ← first child of g
+	And t2 ← second child of g
+	Then t1.p1 = parser.vertices[1]
+	And t1.p2 = parser.vertices[2]
+	And t1.p3 = parser.vertices[3]
+	And t2.p1 = parser.vertices[1]
+	And t2.p2 = parser.vertices[3]
+	And t2.p3 = parser.vertices[4]
+*/
+func TestObjTriangleFaces(t *testing.T) {
+	file := `v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+
+f 1 2 3
+f 1 3 4`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.Nil(t, err)
+	g := obj.DefaultGroup
+	require.Equal(t, 2, len(g.Children()))
+	t1 := g.Children()[0].(*Triangle)
+	t2 := g.Children()[1].(*Triangle)
+	assert.Equal(t, obj.Vertices[0], t1.P1)
+	assert.Equal(t, obj.Vertices[1], t1.P2)
+	assert.Equal(t, obj.Vertices[2], t1.P3)
+	assert.Equal(t, obj.Vertices[0], t2.P1)
+	assert.Equal(t, obj.Vertices[2], t2.P2)
+	assert.Equal(t, obj.Vertices[3], t2.P3)
+}
+
+/*
+	Scenario: Triangulating polygons
+	Given file ← a file containing:
+		"""
+		v -1 1 0
+		v -1 0 0
+		v 1 0 0
+		v 1 1 0
+		v 0 2 0
+
+		f 1 2 3 4 5
+		"""
+	When parser ← parse_obj_file(file)
+	And g ← parser.default_group
+	And t1 ← first child of g
+	And t2 ← second child of g
+	And t3 ← third child of g
+	Then t1.p1 = parser.vertices[1]
+	And t1.p2 = parser.vertices[2]
+	And t1.p3 = parser.vertices[3]
+	And t2.p1 = parser.vertices[1]
+	And t2.p2 = parser.vertices[3]
+	And t2.p3 = parser.vertices[4]
+	And t3.p1 = parser.vertices[1]
+	And t3.p2 = parser.vertices[4]
+	And t3.p3 = parser.vertices[5]
+*/
+func TestObjTriangulatePolygons(t *testing.T) {
+	file := `v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+v 0 2 0
+
+f 1 2 3 4 5`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.Nil(t, err)
+	g := obj.DefaultGroup
+	require.Equal(t, 3, len(g.Children()))
+	t1 := g.Children()[0].(*Triangle)
+	t2 := g.Children()[1].(*Triangle)
+	t3 := g.Children()[2].(*Triangle)
+	assert.Equal(t, obj.Vertices[0], t1.P1)
+	assert.Equal(t, obj.Vertices[1], t1.P2)
+	assert.Equal(t, obj.Vertices[2], t1.P3)
+	assert.Equal(t, obj.Vertices[0], t2.P1)
+	assert.Equal(t, obj.Vertices[2], t2.P2)
+	assert.Equal(t, obj.Vertices[3], t2.P3)
+	assert.Equal(t, obj.Vertices[0], t3.P1)
+	assert.Equal(t, obj.Vertices[3], t3.P2)
+	assert.Equal(t, obj.Vertices[4], t3.P3)
+}
+
+/*
+	Scenario: Triangles in groups
+	Given file ← the file "triangles.obj":
+		"""
+		v -1 1 0
+		v -1 0 0
+		v 1 0 0
+		v 1 1 0
+
+		g FirstGroup
+		f 1 2 3
+		g SecondGroup
+		f 1 3 4
+		"""
+	When parser ← parse_obj_file(file)
+	And g1 ← "FirstGroup" from parser
+	And g2 ← "SecondGroup" from parser
+	And t1 ← first child of g1
+	And t2 ← first child of g2
+	Then t1.p1 = parser.vertices[1]
+	And t1.p2 = parser.vertices[2]
+	And t1.p3 = parser.vertices[3]
+	And t2.p1 = parser.vertices[1]
+	And t2.p2 = parser.vertices[3]
+	And t2.p3 = parser.vertices[4]
+*/
+func TestObjTrianglesInGroups(t *testing.T) {
+	file := `v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+
+g FirstGroup
+f 1 2 3
+g SecondGroup
+f 1 3 4`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.Nil(t, err)
+	g1 := obj.Groups["FirstGroup"]
+	g2 := obj.Groups["SecondGroup"]
+	require.Equal(t, 1, len(g1.Children()))
+	require.Equal(t, 1, len(g2.Children()))
+	t1 := g1.Children()[0].(*Triangle)
+	t2 := g2.Children()[0].(*Triangle)
+	assert.Equal(t, obj.Vertices[0], t1.P1)
+	assert.Equal(t, obj.Vertices[1], t1.P2)
+	assert.Equal(t, obj.Vertices[2], t1.P3)
+	assert.Equal(t, obj.Vertices[0], t2.P1)
+	assert.Equal(t, obj.Vertices[2], t2.P2)
+	assert.Equal(t, obj.Vertices[3], t2.P3)
+}
+
+/*
+	Scenario: Converting an OBJ file to a group
+	Given file ← the file "triangles.obj"
+	And parser ← parse_obj_file(file)
+	When g ← obj_to_group(parser)
+	Then g includes "FirstGroup" from parser
+	And g includes "SecondGroup" from parser
+*/
+func TestObjToGroup(t *testing.T) {
+	file := `v -1 1 0
+v -1 0 0
+v 1 0 0
+v 1 1 0
+
+g FirstGroup
+f 1 2 3
+g SecondGroup
+f 1 3 4`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.Nil(t, err)
+	g := obj.ToGroup()
+	assert.Contains(t, g.Children(), obj.Groups["FirstGroup"])
+	assert.Contains(t, g.Children(), obj.Groups["SecondGroup"])
+
+	r := Ray{NewPoint(-0.5, 0.5, -2), NewVector(0, 0, 1)}
+	xs, err := Intersect(g, r)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(xs))
+	assert.Equal(t, obj.Groups["FirstGroup"].Children()[0], xs[0].Object)
+}
+
+/*
+	Scenario: Groups nest inside the object they follow
+	Given file ← a file containing:
+		"""
+		v -1 1 0
+		v -1 0 0
+		v 1 0 0
+
+		o Teapot
+		g Lid
+		f 1 2 3
+		g Spout
+		f 1 2 3
+		o Table
+		f 3 2 1
+		"""
+	When parser ← parse_obj_file(file)
+	Then "Teapot" includes "Lid" and "Spout"
+	And obj_to_group(parser) includes "Teapot" and "Table"
+*/
+func TestObjGroupsNestInObjects(t *testing.T) {
+	file := `v -1 1 0
+v -1 0 0
+v 1 0 0
+
+o Teapot
+g Lid
+f 1 2 3
+g Spout
+f 1 2 3
+o Table
+f 3 2 1`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.Nil(t, err)
+	teapot := obj.Groups["Teapot"]
+	assert.Equal(t, []Shape{obj.Groups["Lid"], obj.Groups["Spout"]}, teapot.Children())
+	assert.Equal(t, 1, len(obj.Groups["Table"].Children()))
+	g := obj.ToGroup()
+	assert.Contains(t, g.Children(), teapot)
+	assert.Contains(t, g.Children(), obj.Groups["Table"])
+	assert.NotContains(t, g.Children(), obj.Groups["Lid"])
+}
+
+/*
+	Scenario: Vertex normal records
+	Given file ← a file containing:
+		"""
+		vn 0 0 1
+		vn 0.707 0 -0.707
+		vn 1 2 3
+		"""
+	When parser ← parse_obj_file(file)
+	Then parser.normals[1] = vector(0, 0, 1)
+	And parser.normals[2] = vector(0.707, 0, -0.707)
+	And parser.normals[3] = vector(1, 2, 3)
+*/
+func TestObjVertexNormalRecords(t *testing.T) {
+	file := `vn 0 0 1
+vn 0.707 0 -0.707
+vn 1 2 3`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.Nil(t, err)
+	require.Equal(t, 3, len(obj.Normals))
+	assert.Equal(t, NewVector(0, 0, 1), obj.Normals[0])
+	assert.Equal(t, NewVector(0.707, 0, -0.707), obj.Normals[1])
+	assert.Equal(t, NewVector(1, 2, 3), obj.Normals[2])
+}
+
+/*
+	Scenario: Vertex texture records
+	Given file ← a file containing:
+		"""
+		vt 0.5
+		vt 0.25 0.75
+		vt 0 1 0
+		"""
+	When parser ← parse_obj_file(file)
+	Then parser.textures has 3 entries
+	And parser.textures[2] = (0.25, 0.75, 0)
+*/
+func TestObjVertexTextureRecords(t *testing.T) {
+	file := `vt 0.5
+vt 0.25 0.75
+vt 0 1 0`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.Nil(t, err)
+	require.Equal(t, 3, len(obj.Textures))
+	assert.Equal(t, Tuple{0.25, 0.75, 0, 0}, obj.Textures[1])
+}
+
+/*
+	Scenario: Faces with normals
+	Given file ← a file containing:
+		"""
+		v 0 1 0
+		v -1 0 0
+		v 1 0 0
+
+		vn -1 0 0
+		vn 1 0 0
+		vn 0 1 0
+
+		f 1//3 2//1 3//2
+		f 1/0/3 2/102/1 3/14/2
+		"""
+	When parser ← parse_obj_file(file)
+	And g ← parser.default_group
+	And t1 ← first child of g
+	And t2 ← second child of g
+	Then t1.p1 = parser.vertices[1]
+	And t1.p2 = parser.vertices[2]
+	And t1.p3 = parser.vertices[3]
+	And t1.n1 = parser.normals[3]
+	And t1.n2 = parser.normals[1]
+	And t1.n3 = parser.normals[2]
+	And t2 = t1
+
+	NOTE texture indices must refer to vt records here, so the second face
+	uses f 1/1/3 2/2/1 3/3/2 against three vt lines instead
+*/
+func TestObjFacesWithNormals(t *testing.T) {
+	file := `v 0 1 0
+v -1 0 0
+v 1 0 0
+
+vn -1 0 0
+vn 1 0 0
+vn 0 1 0
+
+vt 0 0
+vt 1 0
+vt 0 1
+
+f 1//3 2//1 3//2
+f 1/1/3 2/2/1 3/3/2`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.Nil(t, err)
+	g := obj.DefaultGroup
+	require.Equal(t, 2, len(g.Children()))
+	t1 := g.Children()[0].(*SmoothTriangle)
+	t2 := g.Children()[1].(*SmoothTriangle)
+	assert.Equal(t, obj.Vertices[0], t1.P1)
+	assert.Equal(t, obj.Vertices[1], t1.P2)
+	assert.Equal(t, obj.Vertices[2], t1.P3)
+	assert.Equal(t, obj.Normals[2], t1.N1)
+	assert.Equal(t, obj.Normals[0], t1.N2)
+	assert.Equal(t, obj.Normals[1], t1.N3)
+	assert.Equal(t, t1, t2)
+}
+
+/*
+	Scenario: Negative indices count back from the last record
+	Given file ← a file containing:
+		"""
+		v -1 1 0
+		v -1 0 0
+		v 1 0 0
+		f -3 -2 -1
+		"""
+	When parser ← parse_obj_file(file)
+	Then the first child of parser.default_group is triangle(vertices[1], vertices[2], vertices[3])
+*/
+func TestObjNegativeIndices(t *testing.T) {
+	file := `v -1 1 0
+v -1 0 0
+v 1 0 0
+f -3 -2 -1`
+	obj, err := ParseObj(strings.NewReader(file))
+	require.Nil(t, err)
+	tr := obj.DefaultGroup.Children()[0].(*Triangle)
+	assert.Equal(t, obj.Vertices[0], tr.P1)
+	assert.Equal(t, obj.Vertices[1], tr.P2)
+	assert.Equal(t, obj.Vertices[2], tr.P3)
+}
+
+/*
+	Scenario Outline: Malformed records are reported with their line number
+	Given file ← a file containing <contents>
+	When parser ← parse_obj_file(file)
+	Then the error is <message>
+
+	Examples:
+		| contents                       | message                                           |
+		| v 1 2 3\nv 1 x 3               | line 2: invalid number "x"                        |
+		| v 1 2\n                        | line 1: expected 3 to 4 numbers, got 2            |
+		| v 1 2 3\nv 1 2 3\nf 1 2        | line 3: face needs at least 3 vertices, got 2     |
+		| v 1 2 3\n# comment\nf 1 2 3    | line 3: vertex index 2 out of range, have 1       |
+		| v 1 2 3\nf 1//1 1//1 1//1      | line 2: normal index 1 out of range, have 0       |
+		| g                              | line 1: g statement without a name                |
+*/
+func TestObjMalformedRecords(t *testing.T) {
+	examples := []struct {
+		contents string
+		message  string
+	}{
+		{"v 1 2 3\nv 1 x 3", `line 2: invalid number "x"`},
+		{"v 1 2\n", "line 1: expected 3 to 4 numbers, got 2"},
+		{"v 1 2 3\nv 1 2 3\nf 1 2", "line 3: face needs at least 3 vertices, got 2"},
+		{"v 1 2 3\n# comment\nf 1 2 3", "line 3: vertex index 2 out of range, have 1"},
+		{"v 1 2 3\nf 1//1 1//1 1//1", "line 2: normal index 1 out of range, have 0"},
+		{"g", "line 1: g statement without a name"},
+	}
+	for _, e := range examples {
+		_, err := ParseObj(strings.NewReader(e.contents))
+		require.NotNil(t, err, e.contents)
+		assert.Equal(t, e.message, err.Error())
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"math"
+)
+
+// Pattern - color that varies over a surface. Implementations only deal with
+// pattern space, PatternAtShape handles the conversion from world space
+type Pattern interface {
+	Transform() Mat4
+	SetTransform(m Mat4)
+	// Inverse - inverse of the transform, worked out when the transform is set
+	Inverse() (Mat4, error)
+
+	// PatternAt - color at a point already converted to pattern space
+	PatternAt(p Tuple) Color
+}
+
+// pattern - transform shared by every Pattern, meant to be embedded
+type pattern struct {
+	transform  Mat4
+	inverse    Mat4
+	inverseErr error
+}
+
+func newPattern() pattern {
+	return pattern{
+		transform: NewIdentityMat4(),
+		inverse:   NewIdentityMat4(),
+	}
+}
+
+func (p *pattern) Transform() Mat4 {
+	return p.transform
+}
+
+// SetTransform - set the transform and the inverse cached from it
+func (p *pattern) SetTransform(m Mat4) {
+	p.transform = m
+	p.inverse, p.inverseErr = m.Inverse()
+}
+
+func (p *pattern) Inverse() (Mat4, error) {
+	return p.inverse, p.inverseErr
+}
+
+// PatternAtShape - color of the pattern at a world space point on the shape.
+// The point goes through the shape's transforms into object space, then
+// through the pattern's own transform. Panics if a transform can't be inverted
+func PatternAtShape(pat Pattern, s Shape, worldPoint Tuple) Color {
+	return patternAt(pat, WorldToObject(s, worldPoint))
+}
+
+// patternAt - color of pat at a point in the space pat is placed in, the
+// object for a material's pattern or the parent pattern for a nested one.
+// Panics if the transform can't be inverted
+func patternAt(pat Pattern, p Tuple) Color {
+	inv, err := pat.Inverse()
+	if err != nil {
+		panic(err)
+	}
+	return pat.PatternAt(inv.MulT(p))
+}
+
+// SolidPattern - the same color everywhere, lets a plain color stand in
+// wherever a pattern is nested
+type SolidPattern struct {
+	pattern
+	Color Color
+}
+
+func NewSolidPattern(c Color) *SolidPattern {
+	return &SolidPattern{newPattern(), c}
+}
+
+func (p *SolidPattern) PatternAt(point Tuple) Color {
+	return p.Color
+}
+
+// A and B of the patterns below are patterns themselves, each placed in the
+// parent's pattern space by its own transform. Use SolidPattern for plain colors
+
+// StripePattern - alternates between A and B every unit along x
+type StripePattern struct {
+	pattern
+	A Pattern
+	B Pattern
+}
+
+func NewStripePattern(a, b Pattern) *StripePattern {
+	return &StripePattern{newPattern(), a, b}
+}
+
+func (p *StripePattern) WithTransform(t Mat4) *StripePattern {
+	p.SetTransform(t)
+	return p
+}
+
+func (p *StripePattern) PatternAt(point Tuple) Color {
+	if int(math.Floor(point.X))%2 == 0 {
+		return patternAt(p.A, point)
+	}
+	return patternAt(p.B, point)
+}
+
+// GradientPattern - blends linearly from A to B between x = 0 and 1, repeating every unit
+type GradientPattern struct {
+	pattern
+	A Pattern
+	B Pattern
+}
+
+func NewGradientPattern(a, b Pattern) *GradientPattern {
+	return &GradientPattern{newPattern(), a, b}
+}
+
+func (p *GradientPattern) WithTransform(t Mat4) *GradientPattern {
+	p.SetTransform(t)
+	return p
+}
+
+func (p *GradientPattern) PatternAt(point Tuple) Color {
+	a, b := patternAt(p.A, point), patternAt(p.B, point)
+	fraction := point.X - math.Floor(point.X)
+	return a.Add(b.Sub(a).MulS(fraction))
+}
+
+// RingPattern - concentric rings around the y axis, alternating every unit of radius
+type RingPattern struct {
+	pattern
+	A Pattern
+	B Pattern
+}
+
+func NewRingPattern(a, b Pattern) *RingPattern {
+	return &RingPattern{newPattern(), a, b}
+}
+
+func (p *RingPattern) WithTransform(t Mat4) *RingPattern {
+	p.SetTransform(t)
+	return p
+}
+
+func (p *RingPattern) PatternAt(point Tuple) Color {
+	if int(math.Floor(math.Sqrt(square(point.X)+square(point.Z))))%2 == 0 {
+		return patternAt(p.A, point)
+	}
+	return patternAt(p.B, point)
+}
+
+// CheckerPattern - unit cubes alternating between A and B in all three dimensions
+type CheckerPattern struct {
+	pattern
+	A Pattern
+	B Pattern
+}
+
+func NewCheckerPattern(a, b Pattern) *CheckerPattern {
+	return &CheckerPattern{newPattern(), a, b}
+}
+
+func (p *CheckerPattern) WithTransform(t Mat4) *CheckerPattern {
+	p.SetTransform(t)
+	return p
+}
+
+func (p *CheckerPattern) PatternAt(point Tuple) Color {
+	sum := math.Floor(point.X) + math.Floor(point.Y) + math.Floor(point.Z)
+	if int(sum)%2 == 0 {
+		return patternAt(p.A, point)
+	}
+	return patternAt(p.B, point)
+}
+
+// BlendPattern - average of A and B at every point
+type BlendPattern struct {
+	pattern
+	A Pattern
+	B Pattern
+}
+
+func NewBlendPattern(a, b Pattern) *BlendPattern {
+	return &BlendPattern{newPattern(), a, b}
+}
+
+func (p *BlendPattern) WithTransform(t Mat4) *BlendPattern {
+	p.SetTransform(t)
+	return p
+}
+
+func (p *BlendPattern) PatternAt(point Tuple) Color {
+	return patternAt(p.A, point).Add(patternAt(p.B, point)).MulS(0.5)
+}
+
+// PerturbedPattern - Pattern looked up at a point jittered by Perlin noise,
+// breaks up straight edges into something like marble. Scale is how far the
+// point can move, around 0.2 to 0.5 of a unit looks natural
+type PerturbedPattern struct {
+	pattern
+	Pattern Pattern
+	Scale   float64
+}
+
+func NewPerturbedPattern(p Pattern, scale float64) *PerturbedPattern {
+	return &PerturbedPattern{newPattern(), p, scale}
+}
+
+func (p *PerturbedPattern) WithTransform(t Mat4) *PerturbedPattern {
+	p.SetTransform(t)
+	return p
+}
+
+func (p *PerturbedPattern) PatternAt(point Tuple) Color {
+	// offset z between lookups so each axis gets its own noise
+	x, y, z := point.X, point.Y, point.Z
+	jittered := NewPoint(
+		x+PerlinNoise(x, y, z)*p.Scale,
+		y+PerlinNoise(x, y, z+1)*p.Scale,
+		z+PerlinNoise(x, y, z+2)*p.Scale,
+	)
+	return patternAt(p.Pattern, jittered)
+}
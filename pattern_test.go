@@ -0,0 +1,401 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPattern - colors each point with its own coordinates, shows exactly
+// which point a pattern was asked for
+type testPattern struct {
+	pattern
+}
+
+func newTestPattern() *testPattern {
+	return &testPattern{newPattern()}
+}
+
+func (p *testPattern) PatternAt(point Tuple) Color {
+	return Color{point.X, point.Y, point.Z}
+}
+
+/*
+	Background:
+	Given black ← color(0, 0, 0)
+	And white ← color(1, 1, 1)
+*/
+
+/*
+	Scenario: Creating a stripe pattern
+	Given pattern ← stripe_pattern(white, black)
+	Then pattern.a = white
+	And pattern.b = black
+*/
+func TestNewStripePattern(t *testing.T) {
+	p := NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black))
+	assert.Equal(t, NewSolidPattern(White), p.A)
+	assert.Equal(t, NewSolidPattern(Black), p.B)
+}
+
+/*
+	Scenario: A stripe pattern is constant in y and z
+	Given pattern ← stripe_pattern(white, black)
+	Then pattern_at(pattern, point(0, 1, 0)) = white
+	And pattern_at(pattern, point(0, 2, 0)) = white
+	And pattern_at(pattern, point(0, 0, 1)) = white
+	And pattern_at(pattern, point(0, 0, 2)) = white
+*/
+func TestStripePatternConstantInYZ(t *testing.T) {
+	p := NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black))
+	assert.Equal(t, White, p.PatternAt(NewPoint(0, 1, 0)))
+	assert.Equal(t, White, p.PatternAt(NewPoint(0, 2, 0)))
+	assert.Equal(t, White, p.PatternAt(NewPoint(0, 0, 1)))
+	assert.Equal(t, White, p.PatternAt(NewPoint(0, 0, 2)))
+}
+
+/*
+	Scenario: A stripe pattern alternates in x
+	Given pattern ← stripe_pattern(white, black)
+	Then pattern_at(pattern, point(0, 0, 0)) = white
+	And pattern_at(pattern, point(0.9, 0, 0)) = white
+	And pattern_at(pattern, point(1, 0, 0)) = black
+	And pattern_at(pattern, point(-0.1, 0, 0)) = black
+	And pattern_at(pattern, point(-1, 0, 0)) = black
+	And pattern_at(pattern, point(-1.1, 0, 0)) = white
+*/
+func TestStripePatternAlternatesInX(t *testing.T) {
+	p := NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black))
+	assert.Equal(t, White, p.PatternAt(NewPoint(0, 0, 0)))
+	assert.Equal(t, White, p.PatternAt(NewPoint(0.9, 0, 0)))
+	assert.Equal(t, Black, p.PatternAt(NewPoint(1, 0, 0)))
+	assert.Equal(t, Black, p.PatternAt(NewPoint(-0.1, 0, 0)))
+	assert.Equal(t, Black, p.PatternAt(NewPoint(-1, 0, 0)))
+	assert.Equal(t, White, p.PatternAt(NewPoint(-1.1, 0, 0)))
+}
+
+/*
+	Scenario: Stripes with an object transformation
+	Given object ← sphere()
+	And set_transform(object, scaling(2, 2, 2))
+	And pattern ← stripe_pattern(white, black)
+	When c ← pattern_at_shape(pattern, object, point(1.5, 0, 0))
+	Then c = white
+*/
+func TestStripesWithObjectTransformation(t *testing.T) {
+	object := NewSphere().WithTransform(NewScaling(2, 2, 2))
+	p := NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black))
+	assert.Equal(t, White, PatternAtShape(p, object, NewPoint(1.5, 0, 0)))
+}
+
+/*
+	Scenario: Stripes with a pattern transformation
+	Given object ← sphere()
+	And pattern ← stripe_pattern(white, black)
+	And set_pattern_transform(pattern, scaling(2, 2, 2))
+	When c ← pattern_at_shape(pattern, object, point(1.5, 0, 0))
+	Then c = white
+*/
+func TestStripesWithPatternTransformation(t *testing.T) {
+	object := NewSphere()
+	p := NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black)).WithTransform(NewScaling(2, 2, 2))
+	assert.Equal(t, White, PatternAtShape(p, object, NewPoint(1.5, 0, 0)))
+}
+
+/*
+	Scenario: Stripes with both an object and a pattern transformation
+	Given object ← sphere()
+	And set_transform(object, scaling(2, 2, 2))
+	And pattern ← stripe_pattern(white, black)
+	And set_pattern_transform(pattern, translation(0.5, 0, 0))
+	When c ← pattern_at_shape(pattern, object, point(2.5, 0, 0))
+	Then c = white
+*/
+func TestStripesWithObjectAndPatternTransformation(t *testing.T) {
+	object := NewSphere().WithTransform(NewScaling(2, 2, 2))
+	p := NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black)).WithTransform(NewTranslation(0.5, 0, 0))
+	assert.Equal(t, White, PatternAtShape(p, object, NewPoint(2.5, 0, 0)))
+}
+
+/*
+	Scenario: The default pattern transformation
+	Given pattern ← test_pattern()
+	Then pattern.transform = identity_matrix
+*/
+func TestDefaultPatternTransformation(t *testing.T) {
+	p := newTestPattern()
+	assert.Equal(t, NewIdentityMat4(), p.Transform())
+}
+
+/*
+	Scenario: Assigning a transformation
+	Given pattern ← test_pattern()
+	When set_pattern_transform(pattern, translation(1, 2, 3))
+	Then pattern.transform = translation(1, 2, 3)
+	And pattern.inverse = translation(-1, -2, -3)
+*/
+func TestAssignPatternTransformation(t *testing.T) {
+	p := newTestPattern()
+	p.SetTransform(NewTranslation(1, 2, 3))
+	assert.Equal(t, NewTranslation(1, 2, 3), p.Transform())
+	inv, err := p.Inverse()
+	require.Nil(t, err)
+	assert.True(t, inv.Equal(NewTranslation(-1, -2, -3)))
+}
+
+/*
+	Scenario: A pattern with a transform that can't be inverted panics when used
+	Given pattern ← test_pattern()
+	When set_pattern_transform(pattern, scaling(0, 1, 1))
+	Then pattern_at_shape(pattern, sphere(), point(1, 0, 0)) panics
+*/
+func TestPatternNonInvertibleTransform(t *testing.T) {
+	p := newTestPattern()
+	p.SetTransform(NewScaling(0, 1, 1))
+	_, err := p.Inverse()
+	assert.NotNil(t, err)
+	assert.Panics(t, func() { PatternAtShape(p, NewSphere(), NewPoint(1, 0, 0)) })
+}
+
+/*
+	Scenario Outline: Transforming a point into pattern space
+	Given shape ← sphere()
+	And set_transform(shape, <object_transform>)
+	And pattern ← test_pattern()
+	And set_pattern_transform(pattern, <pattern_transform>)
+	When c ← pattern_at_shape(pattern, shape, <point>)
+	Then c = <color>
+
+	Examples:
+		| object_transform | pattern_transform     | point              | color                 |
+		| scaling(2, 2, 2) | identity_matrix       | point(2, 3, 4)     | color(1, 1.5, 2)      |
+		| identity_matrix  | scaling(2, 2, 2)      | point(2, 3, 4)     | color(1, 1.5, 2)      |
+		| scaling(2, 2, 2) | translation(0.5, 1, 1.5) | point(2.5, 3, 3.5) | color(0.75, 0.5, 0.25) |
+*/
+func TestPatternAtShapeTransforms(t *testing.T) {
+	examples := []struct {
+		objectTransform  Mat4
+		patternTransform Mat4
+		point            Tuple
+		color            Color
+	}{
+		{NewScaling(2, 2, 2), NewIdentityMat4(), NewPoint(2, 3, 4), Color{1, 1.5, 2}},
+		{NewIdentityMat4(), NewScaling(2, 2, 2), NewPoint(2, 3, 4), Color{1, 1.5, 2}},
+		{NewScaling(2, 2, 2), NewTranslation(0.5, 1, 1.5), NewPoint(2.5, 3, 3.5), Color{0.75, 0.5, 0.25}},
+	}
+	for _, e := range examples {
+		s := NewSphere().WithTransform(e.objectTransform)
+		p := newTestPattern()
+		p.SetTransform(e.patternTransform)
+		c := PatternAtShape(p, s, e.point)
+		assert.True(t, c.Equal(e.color), "%v", c)
+	}
+}
+
+/*
+	Scenario: A pattern on a shape in a group
+	Given g ← group()
+	And set_transform(g, scaling(2, 2, 2))
+	And s ← sphere()
+	And set_transform(s, translation(5, 0, 0))
+	And add_child(g, s)
+	And pattern ← test_pattern()
+	When c ← pattern_at_shape(pattern, s, point(12, 4, 6))
+	Then c = color(1, 2, 3)
+*/
+func TestPatternAtShapeInGroup(t *testing.T) {
+	g := NewGroup()
+	g.SetTransform(NewScaling(2, 2, 2))
+	s := NewSphere().WithTransform(NewTranslation(5, 0, 0))
+	g.AddChild(s)
+	c := PatternAtShape(newTestPattern(), s, NewPoint(12, 4, 6))
+	assert.True(t, c.Equal(Color{1, 2, 3}), "%v", c)
+}
+
+/*
+	Scenario: A gradient linearly interpolates between colors
+	Given pattern ← gradient_pattern(white, black)
+	Then pattern_at(pattern, point(0, 0, 0)) = white
+	And pattern_at(pattern, point(0.25, 0, 0)) = color(0.75, 0.75, 0.75)
+	And pattern_at(pattern, point(0.5, 0, 0)) = color(0.5, 0.5, 0.5)
+	And pattern_at(pattern, point(0.75, 0, 0)) = color(0.25, 0.25, 0.25)
+*/
+func TestGradientPattern(t *testing.T) {
+	p := NewGradientPattern(NewSolidPattern(White), NewSolidPattern(Black))
+	assert.True(t, p.PatternAt(NewPoint(0, 0, 0)).Equal(White))
+	assert.True(t, p.PatternAt(NewPoint(0.25, 0, 0)).Equal(Color{0.75, 0.75, 0.75}))
+	assert.True(t, p.PatternAt(NewPoint(0.5, 0, 0)).Equal(Color{0.5, 0.5, 0.5}))
+	assert.True(t, p.PatternAt(NewPoint(0.75, 0, 0)).Equal(Color{0.25, 0.25, 0.25}))
+}
+
+/*
+	Scenario: A ring should extend in both x and z
+	Given pattern ← ring_pattern(white, black)
+	Then pattern_at(pattern, point(0, 0, 0)) = white
+	And pattern_at(pattern, point(1, 0, 0)) = black
+	And pattern_at(pattern, point(0, 0, 1)) = black
+	# 0.708 = just slightly more than √2/2
+	And pattern_at(pattern, point(0.708, 0, 0.708)) = black
+*/
+func TestRingPattern(t *testing.T) {
+	p := NewRingPattern(NewSolidPattern(White), NewSolidPattern(Black))
+	assert.Equal(t, White, p.PatternAt(NewPoint(0, 0, 0)))
+	assert.Equal(t, Black, p.PatternAt(NewPoint(1, 0, 0)))
+	assert.Equal(t, Black, p.PatternAt(NewPoint(0, 0, 1)))
+	assert.Equal(t, Black, p.PatternAt(NewPoint(0.708, 0, 0.708)))
+}
+
+/*
+	Scenario: Checkers should repeat in x
+	Given pattern ← checkers_pattern(white, black)
+	Then pattern_at(pattern, point(0, 0, 0)) = white
+	And pattern_at(pattern, point(0.99, 0, 0)) = white
+	And pattern_at(pattern, point(1.01, 0, 0)) = black
+
+	Scenario: Checkers should repeat in y
+	Given pattern ← checkers_pattern(white, black)
+	Then pattern_at(pattern, point(0, 0, 0)) = white
+	And pattern_at(pattern, point(0, 0.99, 0)) = white
+	And pattern_at(pattern, point(0, 1.01, 0)) = black
+
+	Scenario: Checkers should repeat in z
+	Given pattern ← checkers_pattern(white, black)
+	Then pattern_at(pattern, point(0, 0, 0)) = white
+	And pattern_at(pattern, point(0, 0, 0.99)) = white
+	And pattern_at(pattern, point(0, 0, 1.01)) = black
+
+	Scenario: Checkers continue below zero
+	Given pattern ← checkers_pattern(white, black)
+	Then pattern_at(pattern, point(-0.5, 0, 0)) = black
+	And pattern_at(pattern, point(-0.5, -0.5, 0)) = white
+*/
+func TestCheckerPattern(t *testing.T) {
+	p := NewCheckerPattern(NewSolidPattern(White), NewSolidPattern(Black))
+	examples := []struct {
+		point Tuple
+		color Color
+	}{
+		{NewPoint(0, 0, 0), White},
+		{NewPoint(0.99, 0, 0), White},
+		{NewPoint(1.01, 0, 0), Black},
+		{NewPoint(0, 0.99, 0), White},
+		{NewPoint(0, 1.01, 0), Black},
+		{NewPoint(0, 0, 0.99), White},
+		{NewPoint(0, 0, 1.01), Black},
+		{NewPoint(-0.5, 0, 0), Black},
+		{NewPoint(-0.5, -0.5, 0), White},
+	}
+	for _, e := range examples {
+		assert.Equal(t, e.color, p.PatternAt(e.point), "%v", e.point)
+	}
+}
+
+/*
+	Scenario: A solid pattern is the same color everywhere
+	Given pattern ← solid_pattern(color(0.2, 0.4, 0.6))
+	Then pattern_at(pattern, point(0, 0, 0)) = color(0.2, 0.4, 0.6)
+	And pattern_at(pattern, point(-3.5, 12, 0.25)) = color(0.2, 0.4, 0.6)
+*/
+func TestSolidPattern(t *testing.T) {
+	p := NewSolidPattern(Color{0.2, 0.4, 0.6})
+	assert.Equal(t, Color{0.2, 0.4, 0.6}, p.PatternAt(NewPoint(0, 0, 0)))
+	assert.Equal(t, Color{0.2, 0.4, 0.6}, p.PatternAt(NewPoint(-3.5, 12, 0.25)))
+}
+
+/*
+	Scenario: Checkers of stripes
+	Given red ← stripe_pattern(color(1, 0, 0), color(0.5, 0, 0))
+	And blue ← stripe_pattern(color(0, 0, 1), color(0, 0, 0.5))
+	And pattern ← checkers_pattern(red, blue)
+	Then pattern_at(pattern, point(0.5, 0, 0.5)) = color(1, 0, 0)
+	And pattern_at(pattern, point(1.5, 0, 0.5)) = color(0, 0, 0.5)
+	And pattern_at(pattern, point(0.5, 0, 1.5)) = color(0, 0, 1)
+	And pattern_at(pattern, point(1.5, 0, 1.5)) = color(0.5, 0, 0)
+*/
+func TestNestedPatterns(t *testing.T) {
+	red := NewStripePattern(NewSolidPattern(Color{1, 0, 0}), NewSolidPattern(Color{0.5, 0, 0}))
+	blue := NewStripePattern(NewSolidPattern(Color{0, 0, 1}), NewSolidPattern(Color{0, 0, 0.5}))
+	p := NewCheckerPattern(red, blue)
+	assert.Equal(t, Color{1, 0, 0}, p.PatternAt(NewPoint(0.5, 0, 0.5)))
+	assert.Equal(t, Color{0, 0, 0.5}, p.PatternAt(NewPoint(1.5, 0, 0.5)))
+	assert.Equal(t, Color{0, 0, 1}, p.PatternAt(NewPoint(0.5, 0, 1.5)))
+	assert.Equal(t, Color{0.5, 0, 0}, p.PatternAt(NewPoint(1.5, 0, 1.5)))
+}
+
+/*
+	Scenario: A nested pattern is placed by its own transform inside the parent's space
+	Given child ← test_pattern()
+	And set_pattern_transform(child, scaling(2, 2, 2))
+	And parent ← stripe_pattern(child, child)
+	And set_pattern_transform(parent, translation(1, 0, 0))
+	And object ← sphere() with transform scaling(0.5, 0.5, 0.5)
+	When c ← pattern_at_shape(parent, object, point(1, 1, 1))
+	Then c = color(0.5, 1, 1)
+*/
+func TestNestedPatternTransforms(t *testing.T) {
+	child := newTestPattern()
+	child.SetTransform(NewScaling(2, 2, 2))
+	parent := NewStripePattern(child, child).WithTransform(NewTranslation(1, 0, 0))
+	object := NewSphere().WithTransform(NewScaling(0.5, 0.5, 0.5))
+	// world (1, 1, 1) -> object (2, 2, 2) -> parent (1, 2, 2) -> child (0.5, 1, 1)
+	c := PatternAtShape(parent, object, NewPoint(1, 1, 1))
+	assert.True(t, c.Equal(Color{0.5, 1, 1}), "%v", c)
+}
+
+/*
+	Scenario: A blend pattern averages its children
+	Given a ← stripe_pattern(white, black)
+	And b ← stripe_pattern(white, black) with transform rotation_y(π/2)
+	And pattern ← blend_pattern(a, b)
+	Then pattern_at(pattern, point(0.5, 0, -0.5)) = white
+	And pattern_at(pattern, point(0.5, 0, 0.5)) = color(0.5, 0.5, 0.5)
+	And pattern_at(pattern, point(1.5, 0, -0.5)) = color(0.5, 0.5, 0.5)
+	And pattern_at(pattern, point(1.5, 0, 0.5)) = black
+*/
+func TestBlendPattern(t *testing.T) {
+	a := NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black))
+	b := NewStripePattern(NewSolidPattern(White), NewSolidPattern(Black)).WithTransform(NewRotationY(math.Pi / 2))
+	p := NewBlendPattern(a, b)
+	// b's stripes run along z, white where z is just below 0
+	assert.True(t, p.PatternAt(NewPoint(0.5, 0, -0.5)).Equal(White))
+	assert.True(t, p.PatternAt(NewPoint(0.5, 0, 0.5)).Equal(Color{0.5, 0.5, 0.5}))
+	assert.True(t, p.PatternAt(NewPoint(1.5, 0, -0.5)).Equal(Color{0.5, 0.5, 0.5}))
+	assert.True(t, p.PatternAt(NewPoint(1.5, 0, 0.5)).Equal(Black))
+}
+
+/*
+	Scenario: A perturbed pattern with no scale looks up the point unchanged
+	Given pattern ← perturbed_pattern(test_pattern(), 0)
+	Then pattern_at(pattern, point(0.3, 1.7, -2.2)) = color(0.3, 1.7, -2.2)
+*/
+func TestPerturbedPatternZeroScale(t *testing.T) {
+	p := NewPerturbedPattern(newTestPattern(), 0)
+	assert.True(t, p.PatternAt(NewPoint(0.3, 1.7, -2.2)).Equal(Color{0.3, 1.7, -2.2}))
+}
+
+/*
+	Scenario: A perturbed pattern moves the lookup point by at most its scale on each axis
+	Given pattern ← perturbed_pattern(test_pattern(), 0.25)
+	When c ← pattern_at(pattern, p) for points p across a few units
+	Then every |c - p| ≤ 0.25 on each axis
+	And some c ≠ p
+	And the same point always gives the same color
+*/
+func TestPerturbedPatternJitter(t *testing.T) {
+	p := NewPerturbedPattern(newTestPattern(), 0.25)
+	moved := false
+	for x := -2.0; x < 2; x += 0.37 {
+		for z := -2.0; z < 2; z += 0.41 {
+			point := NewPoint(x, 0.5, z)
+			c := p.PatternAt(point)
+			assert.InDelta(t, x, c.Red, 0.25)
+			assert.InDelta(t, 0.5, c.Green, 0.25)
+			assert.InDelta(t, z, c.Blue, 0.25)
+			moved = moved || !c.Equal(Color{x, 0.5, z})
+			assert.Equal(t, c, p.PatternAt(point))
+		}
+	}
+	assert.True(t, moved)
+}
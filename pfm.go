@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+)
+
+// Portable Float Map files keep each channel as a 32 bit float, so nothing is
+// clamped and values come back exactly as float32 rounds them. Rows are stored
+// bottom to top
+
+// ToPFM - write canvas to given file name in PFM format
+func (c Canvas) ToPFM(fn string) error {
+	return createImage(fn, c.WritePFM)
+}
+
+// WritePFM - write canvas to w as a little endian color PFM
+func (c Canvas) WritePFM(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "PF\n%v %v\n-1.0\n", c.Width, c.Height)
+	var buf [12]byte
+	for y := c.Height - 1; y >= 0; y-- {
+		for x := 0; x < c.Width; x++ {
+			p := c.PixelAt(x, y)
+			binary.LittleEndian.PutUint32(buf[0:], math.Float32bits(float32(p.Red)))
+			binary.LittleEndian.PutUint32(buf[4:], math.Float32bits(float32(p.Green)))
+			binary.LittleEndian.PutUint32(buf[8:], math.Float32bits(float32(p.Blue)))
+			bw.Write(buf[:])
+		}
+	}
+	return bw.Flush()
+}
+
+// LoadPFM - read the PFM file with the given name
+func LoadPFM(fn string) (Canvas, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return Canvas{}, err
+	}
+	defer f.Close()
+
+	c, err := ParsePFM(f)
+	if err != nil {
+		return Canvas{}, fmt.Errorf("%v: %v", fn, err)
+	}
+	return c, nil
+}
+
+// ParsePFM - read a color (PF) or greyscale (Pf) PFM into a canvas. The sign
+// of the scale gives the byte order, negative is little endian
+func ParsePFM(r io.Reader) (Canvas, error) {
+	br := bufio.NewReader(r)
+
+	// the header is whitespace separated like PPM, without comments
+	magic, err := readPPMToken(br)
+	if err != nil {
+		return Canvas{}, err
+	}
+	channels := 0
+	switch magic {
+	case "PF":
+		channels = 3
+	case "Pf":
+		channels = 1
+	default:
+		return Canvas{}, fmt.Errorf("unsupported magic number %q, expected PF or Pf", magic)
+	}
+
+	width, err := readPPMInt(br, "width")
+	if err != nil {
+		return Canvas{}, err
+	}
+	height, err := readPPMInt(br, "height")
+	if err != nil {
+		return Canvas{}, err
+	}
+	if err := checkImageSize(width, height); err != nil {
+		return Canvas{}, err
+	}
+	token, err := readPPMToken(br)
+	if err != nil {
+		return Canvas{}, fmt.Errorf("reading scale: %v", err)
+	}
+	scale, err := strconv.ParseFloat(token, 64)
+	if err != nil || scale == 0 {
+		return Canvas{}, fmt.Errorf("invalid scale %q", token)
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if scale < 0 {
+		order = binary.LittleEndian
+	}
+
+	c := NewCanvas(width, height)
+	buf := make([]byte, 4*channels)
+	for y := height - 1; y >= 0; y-- {
+		for x := 0; x < width; x++ {
+			if _, err := io.ReadFull(br, buf); err != nil {
+				return Canvas{}, fmt.Errorf("pixel %v, %v: %v", x, y, err)
+			}
+			v := [3]float64{}
+			for i := range v {
+				v[i] = float64(math.Float32frombits(order.Uint32(buf[4*(i%channels):])))
+			}
+			c.WritePixel(x, y, Color{v[0], v[1], v[2]})
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pfmFloats - float32s in the given byte order, as stored in a PFM raster
+func pfmFloats(order binary.ByteOrder, values ...float32) []byte {
+	b := make([]byte, 4*len(values))
+	for i, v := range values {
+		order.PutUint32(b[4*i:], math.Float32bits(v))
+	}
+	return b
+}
+
+/*
+	Scenario: Constructing a PFM file
+	Given c ← canvas(1, 2)
+	And write_pixel(c, 0, 0, color(1.5, 0, 2))
+	And write_pixel(c, 0, 1, color(-0.5, 100, 0.25))
+	When pfm ← canvas_to_pfm(c)
+	Then pfm starts with "PF\n1 2\n-1.0\n"
+	And the rest of pfm is little endian floats, bottom row first
+		| -0.5 | 100 | 0.25 |
+		| 1.5  | 0   | 2    |
+*/
+func TestWritePFM(t *testing.T) {
+	c := NewCanvas(1, 2)
+	c.WritePixel(0, 0, Color{1.5, 0, 2})
+	c.WritePixel(0, 1, Color{-0.5, 100, 0.25})
+	buf := bytes.Buffer{}
+	require.Nil(t, c.WritePFM(&buf))
+	expected := append([]byte("PF\n1 2\n-1.0\n"), pfmFloats(binary.LittleEndian, -0.5, 100, 0.25, 1.5, 0, 2)...)
+	assert.Equal(t, expected, buf.Bytes())
+}
+
+/*
+	Scenario: Reading a big endian PFM file
+	Given pfm ← "PF\n2 1\n1.0\n" followed by big endian floats 1 2 3 4 5 6
+	When c ← pfm_to_canvas(pfm)
+	Then pixel_at(c, 0, 0) = color(1, 2, 3)
+	And pixel_at(c, 1, 0) = color(4, 5, 6)
+*/
+func TestParsePFMBigEndian(t *testing.T) {
+	pfm := append([]byte("PF\n2 1\n1.0\n"), pfmFloats(binary.BigEndian, 1, 2, 3, 4, 5, 6)...)
+	c, err := ParsePFM(bytes.NewReader(pfm))
+	require.Nil(t, err)
+	assert.Equal(t, Color{1, 2, 3}, c.PixelAt(0, 0))
+	assert.Equal(t, Color{4, 5, 6}, c.PixelAt(1, 0))
+}
+
+/*
+	Scenario: Reading a greyscale PFM file
+	Given pfm ← "Pf\n1 2\n-1.0\n" followed by little endian floats 0.5 8
+	When c ← pfm_to_canvas(pfm)
+	Then pixel_at(c, 0, 0) = color(8, 8, 8)
+	And pixel_at(c, 0, 1) = color(0.5, 0.5, 0.5)
+*/
+func TestParsePFMGreyscale(t *testing.T) {
+	pfm := append([]byte("Pf\n1 2\n-1.0\n"), pfmFloats(binary.LittleEndian, 0.5, 8)...)
+	c, err := ParsePFM(bytes.NewReader(pfm))
+	require.Nil(t, err)
+	assert.Equal(t, Color{8, 8, 8}, c.PixelAt(0, 0))
+	assert.Equal(t, Color{0.5, 0.5, 0.5}, c.PixelAt(0, 1))
+}
+
+/*
+	Scenario Outline: Reading an invalid PFM file is an error
+	Given pfm ← <pfm>
+	Then pfm_to_canvas(pfm) is an error
+
+	Examples:
+		| pfm                          | reason            |
+		| "P6\n1 1\n255\n"             | not a float map   |
+		| "PF\n1 1\nx\n"               | invalid scale     |
+		| "PF\n1 1\n0\n"               | zero scale        |
+		| "PF\n0 1\n-1.0\n"            | empty image       |
+		| "PF\n1 1\n-1.0\n\x00\x00"    | truncated raster  |
+		| "PF\n100000 100000\n-1.0\n" | too large to allocate |
+*/
+func TestParsePFMInvalid(t *testing.T) {
+	examples := []string{
+		"P6\n1 1\n255\n",
+		"PF\n1 1\nx\n",
+		"PF\n1 1\n0\n",
+		"PF\n0 1\n-1.0\n",
+		"PF\n1 1\n-1.0\n\x00\x00",
+		"PF\n100000 100000\n-1.0\n",
+	}
+	for _, pfm := range examples {
+		_, err := ParsePFM(strings.NewReader(pfm))
+		assert.NotNil(t, err, "%q", pfm)
+	}
+}
+
+/*
+	Scenario: A canvas round trips through a PFM file
+	Given c ← canvas(10, 7) with colors outside 0 to 1
+	When canvas_to_pfm(c) is written to a file
+	And c2 ← load_pfm(file)
+	Then every channel of c2 = float32(channel of c)
+*/
+func TestPFMRoundTrip(t *testing.T) {
+	fn := "test.pfm"
+	c := NewCanvas(10, 7)
+	for x := 0; x < c.Width; x++ {
+		for y := 0; y < c.Height; y++ {
+			c.WritePixel(x, y, Color{float64(x*y) * 12.3, -float64(x) / 7, 1e6})
+		}
+	}
+	require.Nil(t, c.ToPFM(fn))
+	c2, err := LoadPFM(fn)
+	require.Nil(t, err)
+	require.Nil(t, os.Remove(fn))
+
+	require.Equal(t, c.Width, c2.Width)
+	require.Equal(t, c.Height, c2.Height)
+	for x := 0; x < c.Width; x++ {
+		for y := 0; y < c.Height; y++ {
+			p := c.PixelAt(x, y)
+			expected := Color{float64(float32(p.Red)), float64(float32(p.Green)), float64(float32(p.Blue))}
+			assert.Equal(t, expected, c2.PixelAt(x, y))
+		}
+	}
+
+	_, err = LoadPFM("missing.pfm")
+	assert.NotNil(t, err)
+}
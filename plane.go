@@ -0,0 +1,44 @@
+package main
+
+import (
+	"math"
+)
+
+// Plane - infinite plane in xz, normal pointing up the y axis
+type Plane struct {
+	shape
+}
+
+func NewPlane() *Plane {
+	return &Plane{newShape()}
+}
+
+func (p *Plane) WithTransform(t Mat4) *Plane {
+	p.SetTransform(t)
+	return p
+}
+
+func (p *Plane) WithMaterial(m Material) *Plane {
+	p.SetMaterial(m)
+	return p
+}
+
+func (p *Plane) LocalIntersect(r Ray) Intersections {
+	// parallel to (or within) the plane, there is nothing to see
+	if math.Abs(r.Direction.Y) < epsilon {
+		return Intersections{}
+	}
+	t := -r.Origin.Y / r.Direction.Y
+	return Intersections{NewIntersection(t, p)}
+}
+
+func (p *Plane) LocalNormalAt(_ Tuple, _ Intersection) Tuple {
+	return NewVector(0, 1, 0)
+}
+
+func (p *Plane) Bounds() BoundingBox {
+	return NewBoundingBoxFrom(
+		NewPoint(math.Inf(-1), 0, math.Inf(-1)),
+		NewPoint(math.Inf(1), 0, math.Inf(1)),
+	)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario: The normal of a plane is constant everywhere
+	Given p ← plane()
+	When n1 ← local_normal_at(p, point(0, 0, 0))
+	And n2 ← local_normal_at(p, point(10, 0, -10))
+	And n3 ← local_normal_at(p, point(-5, 0, 150))
+	Then n1 = vector(0, 1, 0)
+	And n2 = vector(0, 1, 0)
+	And n3 = vector(0, 1, 0)
+*/
+func TestPlaneNormalConstant(t *testing.T) {
+	p := NewPlane()
+	n1 := p.LocalNormalAt(NewPoint(0, 0, 0), Intersection{})
+	n2 := p.LocalNormalAt(NewPoint(10, 0, -10), Intersection{})
+	n3 := p.LocalNormalAt(NewPoint(-5, 0, 150), Intersection{})
+	assert.True(t, n1.Equal(NewVector(0, 1, 0)))
+	assert.True(t, n2.Equal(NewVector(0, 1, 0)))
+	assert.True(t, n3.Equal(NewVector(0, 1, 0)))
+}
+
+/*
+	Scenario: Intersect with a ray parallel to the plane
+	Given p ← plane()
+	And r ← ray(point(0, 10, 0), vector(0, 0, 1))
+	When xs ← local_intersect(p, r)
+	Then xs is empty
+*/
+func TestIntersectRayParallelToPlane(t *testing.T) {
+	p := NewPlane()
+	r := Ray{NewPoint(0, 10, 0), NewVector(0, 0, 1)}
+	xs := p.LocalIntersect(r)
+	assert.Equal(t, 0, len(xs))
+}
+
+/*
+	Scenario: Intersect with a coplanar ray
+	Given p ← plane()
+	And r ← ray(point(0, 0, 0), vector(0, 0, 1))
+	When xs ← local_intersect(p, r)
+	Then xs is empty
+*/
+func TestIntersectCoplanarRay(t *testing.T) {
+	p := NewPlane()
+	r := Ray{NewPoint(0, 0, 0), NewVector(0, 0, 1)}
+	xs := p.LocalIntersect(r)
+	assert.Equal(t, 0, len(xs))
+}
+
+/*
+	Scenario: A ray intersecting a plane from above
+	Given p ← plane()
+	And r ← ray(point(0, 1, 0), vector(0, -1, 0))
+	When xs ← local_intersect(p, r)
+	Then xs.count = 1
+	And xs[0].t = 1
+	And xs[0].object = p
+*/
+func TestIntersectPlaneFromAbove(t *testing.T) {
+	p := NewPlane()
+	r := Ray{NewPoint(0, 1, 0), NewVector(0, -1, 0)}
+	xs := p.LocalIntersect(r)
+	require.Equal(t, 1, len(xs))
+	assert.Equal(t, 1.0, xs[0].T)
+	assert.Equal(t, p, xs[0].Object)
+}
+
+/*
+	Scenario: A ray intersecting a plane from below
+	Given p ← plane()
+	And r ← ray(point(0, -1, 0), vector(0, 1, 0))
+	When xs ← local_intersect(p, r)
+	Then xs.count = 1
+	And xs[0].t = 1
+	And xs[0].object = p
+*/
+func TestIntersectPlaneFromBelow(t *testing.T) {
+	p := NewPlane()
+	r := Ray{NewPoint(0, -1, 0), NewVector(0, 1, 0)}
+	xs := p.LocalIntersect(r)
+	require.Equal(t, 1, len(xs))
+	assert.Equal(t, 1.0, xs[0].T)
+	assert.Equal(t, p, xs[0].Object)
+}
+
+/*
+	Scenario: Intersecting a transformed plane
+	Given p ← plane()
+	And set_transform(p, rotation_x(π/2) then translation(0, 0, 5))
+	And r ← ray(point(0, 0, 0), vector(0, 0, 1))
+	When xs ← intersect(p, r)
+	Then xs.count = 1
+	And xs[0].t = 5
+	And normal_at(p, point(0, 0, 5)) = vector(0, 0, 1)
+*/
+func TestIntersectTransformedPlane(t *testing.T) {
+	p := NewPlane().WithTransform(NewRotationX(math.Pi / 2).Translate(0, 0, 5))
+	r := Ray{NewPoint(0, 0, 0), NewVector(0, 0, 1)}
+	xs, err := Intersect(p, r)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(xs))
+	assert.InDelta(t, 5.0, xs[0].T, epsilon)
+	assert.True(t, NormalAt(p, NewPoint(0, 0, 5), Intersection{}).Equal(NewVector(0, 0, 1)))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// ToneMap - operator squeezing linear colors of any brightness into 0 to 1
+type ToneMap int
+
+const (
+	// ToneMapNone - leave colors as they are, for data passes and HDR output
+	ToneMapNone ToneMap = iota
+	// ToneMapClamp - cut every channel off at 0 and 1
+	ToneMapClamp
+	// ToneMapReinhard - c / (1 + c), compresses highlights without clipping
+	ToneMapReinhard
+	// ToneMapACES - Narkowicz's fit of the ACES filmic curve, adds contrast
+	ToneMapACES
+)
+
+// PostProcess - turns the linear colors a render produces into the colors
+// written out: exposure first, then the tone map, then sRGB encoding
+type PostProcess struct {
+	// Exposure - in stops, each one doubles the brightness
+	Exposure float64
+	ToneMap  ToneMap
+	// SRGB - apply the sRGB transfer function, otherwise output stays linear
+	SRGB bool
+}
+
+var (
+	// LinearOutput - colors written exactly as rendered
+	LinearOutput = PostProcess{}
+	// DisplayOutput - clamped and sRGB encoded, what screens and image viewers expect
+	DisplayOutput = PostProcess{ToneMap: ToneMapClamp, SRGB: true}
+)
+
+// Apply - post process a single color
+func (p PostProcess) Apply(c Color) Color {
+	if p.Exposure != 0 {
+		c = c.MulS(math.Exp2(p.Exposure))
+	}
+	c = Color{p.ToneMap.apply(c.Red), p.ToneMap.apply(c.Green), p.ToneMap.apply(c.Blue)}
+	if p.SRGB {
+		c = Color{srgbEncode(c.Red), srgbEncode(c.Green), srgbEncode(c.Blue)}
+	}
+	return c
+}
+
+func (t ToneMap) apply(v float64) float64 {
+	switch t {
+	case ToneMapClamp:
+		return math.Max(0, math.Min(v, 1))
+	case ToneMapReinhard:
+		v = math.Max(v, 0)
+		return v / (1 + v)
+	case ToneMapACES:
+		v = math.Max(v, 0)
+		return math.Min((v*(2.51*v+0.03))/(v*(2.43*v+0.59)+0.14), 1)
+	}
+	return v
+}
+
+// srgbEncode - sRGB transfer function, linear near black and a 2.4 power above
+func srgbEncode(v float64) float64 {
+	if v <= 0 {
+		return 0
+	}
+	if v <= 0.0031308 {
+		return 12.92 * v
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
+
+// PostProcessed - copy of the canvas with p applied to every pixel, ready to
+// be written in any format
+func (c Canvas) PostProcessed(p PostProcess) Canvas {
+	out := NewCanvas(c.Width, c.Height)
+	for x := 0; x < c.Width; x++ {
+		for y := 0; y < c.Height; y++ {
+			out.Pixels[x][y] = p.Apply(c.Pixels[x][y])
+		}
+	}
+	return out
+}
+
+// Save - post process the canvas with p and write it to fn, in the format
+// matching its extension: .ppm (binary), .png, .jpg or .jpeg, .hdr or .pfm
+func (c Canvas) Save(fn string, p PostProcess) error {
+	out := c.PostProcessed(p)
+	switch strings.ToLower(filepath.Ext(fn)) {
+	case ".ppm":
+		return out.ToPPMBinary(fn)
+	case ".png":
+		return out.ToPNG(fn)
+	case ".jpg", ".jpeg":
+		return out.ToJPEG(fn, 90)
+	case ".hdr":
+		return out.ToHDR(fn)
+	case ".pfm":
+		return out.ToPFM(fn)
+	}
+	return fmt.Errorf("%v: unknown image format %q", fn, filepath.Ext(fn))
+}
+
+// SavePNG16 - post process the canvas with p and write it to fn as a PNG with
+// 16 bits per channel, for smooth gradients Save's 8 bit PNG would band
+func (c Canvas) SavePNG16(fn string, p PostProcess) error {
+	return c.PostProcessed(p).ToPNG16(fn)
+}
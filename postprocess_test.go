@@ -0,0 +1,241 @@
+package main
+
+import (
+	"image"
+	"image/png"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario Outline: Tone mapping operators
+	Given op ← <tone_map>
+	Then op(<value>) = <result>
+
+	Examples:
+		| tone_map | value | result  |
+		| none     | 3     | 3       |
+		| none     | -1    | -1      |
+		| clamp    | 3     | 1       |
+		| clamp    | -1    | 0       |
+		| clamp    | 0.25  | 0.25    |
+		| reinhard | 1     | 0.5     |
+		| reinhard | 3     | 0.75    |
+		| reinhard | -1    | 0       |
+		| aces     | 0     | 0       |
+		| aces     | 1     | 0.80380 |
+		| aces     | 100   | 1       |
+*/
+func TestToneMaps(t *testing.T) {
+	examples := []struct {
+		toneMap ToneMap
+		value   float64
+		result  float64
+	}{
+		{ToneMapNone, 3, 3},
+		{ToneMapNone, -1, -1},
+		{ToneMapClamp, 3, 1},
+		{ToneMapClamp, -1, 0},
+		{ToneMapClamp, 0.25, 0.25},
+		{ToneMapReinhard, 1, 0.5},
+		{ToneMapReinhard, 3, 0.75},
+		{ToneMapReinhard, -1, 0},
+		{ToneMapACES, 0, 0},
+		{ToneMapACES, 1, 0.80380},
+		{ToneMapACES, 100, 1},
+	}
+	for _, e := range examples {
+		assert.InDelta(t, e.result, e.toneMap.apply(e.value), 0.00001, "%v(%v)", e.toneMap, e.value)
+	}
+}
+
+/*
+	Scenario Outline: The sRGB transfer function
+	Then srgb(<value>) = <result>
+
+	Examples:
+		| value  | result  |
+		| -0.5   | 0       |
+		| 0      | 0       |
+		| 0.002  | 0.02584 |
+		| 0.18   | 0.46135 |
+		| 0.5    | 0.73536 |
+		| 1      | 1       |
+*/
+func TestSRGBEncode(t *testing.T) {
+	examples := []struct {
+		value  float64
+		result float64
+	}{
+		{-0.5, 0},
+		{0, 0},
+		{0.002, 0.02584},
+		{0.18, 0.46135},
+		{0.5, 0.73536},
+		{1, 1},
+	}
+	for _, e := range examples {
+		assert.InDelta(t, e.result, srgbEncode(e.value), 0.00001, "%v", e.value)
+	}
+}
+
+/*
+	Scenario: Linear output leaves colors alone
+	Given c ← color(2, -1, 0.5)
+	Then apply(linear_output, c) = c
+*/
+func TestLinearOutput(t *testing.T) {
+	c := Color{2, -1, 0.5}
+	assert.Equal(t, c, LinearOutput.Apply(c))
+}
+
+/*
+	Scenario: Exposure is applied before the tone map and sRGB
+	Given p ← post_process(exposure: 1, tone_map: reinhard, srgb: true)
+	When c ← apply(p, color(0.5, 1.5, 0))
+	Then c = color(srgb(0.5), srgb(0.75), 0)
+*/
+func TestPostProcessOrder(t *testing.T) {
+	p := PostProcess{Exposure: 1, ToneMap: ToneMapReinhard, SRGB: true}
+	c := p.Apply(Color{0.5, 1.5, 0})
+	assert.True(t, c.Equal(Color{srgbEncode(0.5), srgbEncode(0.75), 0}))
+}
+
+/*
+	Scenario: Negative exposure darkens
+	Given p ← post_process(exposure: -2)
+	Then apply(p, color(4, 2, 1)) = color(1, 0.5, 0.25)
+*/
+func TestPostProcessNegativeExposure(t *testing.T) {
+	p := PostProcess{Exposure: -2}
+	assert.True(t, p.Apply(Color{4, 2, 1}).Equal(Color{1, 0.5, 0.25}))
+}
+
+/*
+	Scenario: Post processing a canvas leaves the original alone
+	Given c ← canvas(2, 2)
+	And write_pixel(c, 1, 0, color(0.5, 0.5, 0.5))
+	When c2 ← post_processed(c, display_output)
+	Then pixel_at(c2, 1, 0) = color(0.73536, 0.73536, 0.73536)
+	And pixel_at(c, 1, 0) = color(0.5, 0.5, 0.5)
+*/
+func TestCanvasPostProcessed(t *testing.T) {
+	c := NewCanvas(2, 2)
+	c.WritePixel(1, 0, Color{0.5, 0.5, 0.5})
+	c2 := c.PostProcessed(DisplayOutput)
+	assert.InDelta(t, 0.73536, c2.PixelAt(1, 0).Red, 0.00001)
+	assert.True(t, c.PixelAt(1, 0).Equal(Color{0.5, 0.5, 0.5}))
+}
+
+/*
+	Scenario Outline: Saving a canvas picks the format from the extension
+	Given c ← canvas(4, 3) with every pixel color(0.5, 2, 0)
+	When save(c, <file>, <post_process>)
+	And c2 ← the file read back
+	Then pixel_at(c2, 0, 0) is close to <color>
+
+	Examples:
+		| file      | post_process   | color                     |
+		| test.ppm  | display_output | color(0.73725, 1, 0)      |
+		| test.png  | display_output | color(0.73725, 1, 0)      |
+		| test.pfm  | linear_output  | color(0.5, 2, 0)          |
+		| test.hdr  | linear_output  | color(0.5, 2, 0)          |
+		| test.pfm  | reinhard       | color(0.33333, 0.66667, 0)|
+*/
+func TestCanvasSave(t *testing.T) {
+	c := NewCanvas(4, 3)
+	for x := 0; x < c.Width; x++ {
+		for y := 0; y < c.Height; y++ {
+			c.WritePixel(x, y, Color{0.5, 2, 0})
+		}
+	}
+
+	examples := []struct {
+		fn    string
+		p     PostProcess
+		load  func(string) (Canvas, error)
+		color Color
+	}{
+		{"test.ppm", DisplayOutput, LoadPPM, Color{188.0 / 255, 1, 0}},
+		{"test.png", DisplayOutput, LoadImage, Color{188.0 / 255, 1, 0}},
+		{"test.pfm", LinearOutput, LoadPFM, Color{0.5, 2, 0}},
+		{"test.hdr", LinearOutput, LoadHDR, Color{0.5, 2, 0}},
+		{"test.pfm", PostProcess{ToneMap: ToneMapReinhard}, LoadPFM, Color{1.0 / 3, 2.0 / 3, 0}},
+	}
+	for _, e := range examples {
+		require.Nil(t, c.Save(e.fn, e.p))
+		c2, err := e.load(e.fn)
+		require.Nil(t, err)
+		require.Nil(t, os.Remove(e.fn))
+		p := c2.PixelAt(0, 0)
+		assert.InDeltaSlice(t,
+			[]float64{e.color.Red, e.color.Green, e.color.Blue},
+			[]float64{p.Red, p.Green, p.Blue},
+			0.00001, "%v", e.fn,
+		)
+	}
+}
+
+/*
+	Scenario: Saving a 16 bit PNG
+	Given c ← a gradient canvas(1000, 1)
+	When save_png16(c, "test.png", display_output)
+	And img ← decode("test.png")
+	Then img is a 16 bit image
+	And img has 1000 different colors
+	And every pixel of img is within 1/65535 of apply(display_output, c)
+*/
+func TestCanvasSavePNG16(t *testing.T) {
+	c := gradientCanvas(1000, 1)
+	require.Nil(t, c.SavePNG16("test.png", DisplayOutput))
+	f, err := os.Open("test.png")
+	require.Nil(t, err)
+	img, err := png.Decode(f)
+	f.Close()
+	require.Nil(t, os.Remove("test.png"))
+	require.Nil(t, err)
+	_, ok := img.(*image.RGBA64)
+	require.True(t, ok, "expected a 16 bit image, got %T", img)
+
+	c2 := NewCanvasFromImage(img)
+	levels := map[float64]bool{}
+	for x := 0; x < c.Width; x++ {
+		levels[c2.PixelAt(x, 0).Red] = true
+		assert.InDelta(t, DisplayOutput.Apply(c.PixelAt(x, 0)).Red, c2.PixelAt(x, 0).Red, 1.0/0xffff)
+	}
+	assert.Equal(t, 1000, len(levels))
+}
+
+/*
+	Scenario: Saving with an unknown extension is an error
+	Given c ← canvas(1, 1)
+	Then save(c, "test.bmp", display_output) is an error
+*/
+func TestCanvasSaveUnknownFormat(t *testing.T) {
+	c := NewCanvas(1, 1)
+	err := c.Save("test.bmp", DisplayOutput)
+	assert.NotNil(t, err)
+	_, statErr := os.Stat("test.bmp")
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+/*
+	Scenario: Tone mapping keeps bright colors apart that clamping merges
+	Given bright ← color(2, 2, 2)
+	And brighter ← color(4, 4, 4)
+	Then apply(clamp, bright) = apply(clamp, brighter)
+	And apply(aces, bright) < apply(aces, brighter) < 1
+*/
+func TestToneMapKeepsHighlights(t *testing.T) {
+	clamp := PostProcess{ToneMap: ToneMapClamp}
+	aces := PostProcess{ToneMap: ToneMapACES}
+	bright, brighter := Color{2, 2, 2}, Color{4, 4, 4}
+	assert.Equal(t, clamp.Apply(bright), clamp.Apply(brighter))
+	assert.True(t, aces.Apply(bright).Red < aces.Apply(brighter).Red)
+	assert.True(t, aces.Apply(brighter).Red < 1)
+	assert.False(t, math.IsNaN(aces.Apply(brighter).Red))
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// ToPPMBinary - write canvas to given file name in binary (P6) PPM format
+func (c Canvas) ToPPMBinary(fn string) error {
+	f, err := os.Create(fn)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := c.WritePPMBinary(f); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// WritePPMBinary - write canvas to w as a P6 PPM, one byte per channel
+func (c Canvas) WritePPMBinary(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "P6\n%v %v\n255\n", c.Width, c.Height); err != nil {
+		return err
+	}
+	for y := 0; y < c.Height; y++ {
+		for x := 0; x < c.Width; x++ {
+			color := c.PixelAt(x, y)
+			bw.WriteByte(byte(getPixelValue(color.Red)))
+			bw.WriteByte(byte(getPixelValue(color.Green)))
+			bw.WriteByte(byte(getPixelValue(color.Blue)))
+		}
+	}
+	return bw.Flush()
+}
+
+// LoadPPM - read the PPM file with the given name
+func LoadPPM(fn string) (Canvas, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return Canvas{}, err
+	}
+	defer f.Close()
+
+	c, err := ParsePPM(f)
+	if err != nil {
+		return Canvas{}, fmt.Errorf("%v: %v", fn, err)
+	}
+	return c, nil
+}
+
+// ParsePPM - read a P3 (ascii) or P6 (binary) PPM into a canvas. Channels
+// are scaled from 0 to maxval into 0 to 1, whatever maxval the file uses
+func ParsePPM(r io.Reader) (Canvas, error) {
+	br := bufio.NewReader(r)
+
+	magic, err := readPPMToken(br)
+	if err != nil {
+		return Canvas{}, err
+	}
+	if magic != "P3" && magic != "P6" {
+		return Canvas{}, fmt.Errorf("unsupported magic number %q, expected P3 or P6", magic)
+	}
+
+	header := [3]int{}
+	for i, name := range []string{"width", "height", "maxval"} {
+		header[i], err = readPPMInt(br, name)
+		if err != nil {
+			return Canvas{}, err
+		}
+	}
+	width, height, maxval := header[0], header[1], header[2]
+	if err := checkImageSize(width, height); err != nil {
+		return Canvas{}, err
+	}
+	if maxval < 1 || maxval > 65535 {
+		return Canvas{}, fmt.Errorf("maxval %v out of range 1 to 65535", maxval)
+	}
+
+	// P6 samples are one byte, or two bytes big endian when maxval needs them
+	var bytes []byte
+	if maxval > 255 {
+		bytes = make([]byte, 2)
+	} else {
+		bytes = make([]byte, 1)
+	}
+	sample := func(channel string, x, y int) (float64, error) {
+		var v int
+		if magic == "P3" {
+			v, err = readPPMInt(br, channel)
+			if err != nil {
+				return 0, err
+			}
+		} else {
+			if _, err := io.ReadFull(br, bytes); err != nil {
+				return 0, fmt.Errorf("pixel %v, %v: %v", x, y, err)
+			}
+			for _, b := range bytes {
+				v = v<<8 | int(b)
+			}
+		}
+		if v < 0 || v > maxval {
+			return 0, fmt.Errorf("pixel %v, %v: %v %v out of range 0 to %v", x, y, channel, v, maxval)
+		}
+		return float64(v) / float64(maxval), nil
+	}
+
+	c := NewCanvas(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var rgb [3]float64
+			for i, channel := range []string{"red", "green", "blue"} {
+				rgb[i], err = sample(channel, x, y)
+				if err != nil {
+					return Canvas{}, err
+				}
+			}
+			c.WritePixel(x, y, Color{rgb[0], rgb[1], rgb[2]})
+		}
+	}
+	return c, nil
+}
+
+// MaxImagePixels - largest image the readers will load. The size comes from
+// the file's header and the canvas is allocated before any pixels are read,
+// so without a limit a corrupt or hostile header can exhaust memory. A pixel
+// takes 24 bytes in a canvas, the default allows 8192 x 4096
+var MaxImagePixels = 1 << 25
+
+// checkImageSize - error unless a width x height image from a file header
+// can be allocated
+func checkImageSize(width, height int) error {
+	if width < 1 || height < 1 {
+		return fmt.Errorf("invalid size %v x %v", width, height)
+	}
+	if width > MaxImagePixels/height {
+		return fmt.Errorf("image of %v x %v is larger than the limit of %v pixels", width, height, MaxImagePixels)
+	}
+	return nil
+}
+
+// readPPMToken - next whitespace separated token, skipping # comments. The
+// single whitespace byte ending the token is consumed, which is what P6
+// needs between maxval and the raster
+func readPPMToken(br *bufio.Reader) (string, error) {
+	token := []byte{}
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF && len(token) > 0 {
+			return string(token), nil
+		}
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return "", err
+		}
+
+		switch {
+		case b == '#' && len(token) == 0:
+			if _, err := br.ReadString('\n'); err != nil && err != io.EOF {
+				return "", err
+			}
+		case b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\v' || b == '\f':
+			if len(token) > 0 {
+				return string(token), nil
+			}
+		default:
+			token = append(token, b)
+		}
+	}
+}
+
+// readPPMInt - next token as a non negative integer, name is used in errors
+func readPPMInt(br *bufio.Reader, name string) (int, error) {
+	token, err := readPPMToken(br)
+	if err != nil {
+		return 0, fmt.Errorf("reading %v: %v", name, err)
+	}
+	v, err := strconv.Atoi(token)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid %v %q", name, token)
+	}
+	return v, nil
+}
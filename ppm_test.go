@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario: Constructing a binary PPM
+	Given c ← canvas(3, 2)
+	And write_pixel(c, 0, 0, color(1.5, 0, 0))
+	And write_pixel(c, 1, 0, color(0, 0.5, 0))
+	And write_pixel(c, 2, 1, color(-0.5, 0, 1))
+	When ppm ← canvas_to_binary_ppm(c)
+	Then ppm starts with "P6\n3 2\n255\n"
+	And the rest of ppm is bytes
+		| 255 0 0 | 0 128 0 | 0 0 0 |
+		| 0 0 0   | 0 0 0   | 0 0 255 |
+*/
+func TestPPMBinary(t *testing.T) {
+	c := NewCanvas(3, 2)
+	c.WritePixel(0, 0, Color{1.5, 0, 0})
+	c.WritePixel(1, 0, Color{0, 0.5, 0})
+	c.WritePixel(2, 1, Color{-0.5, 0, 1})
+
+	buf := bytes.Buffer{}
+	require.Nil(t, c.WritePPMBinary(&buf))
+
+	expected := append([]byte("P6\n3 2\n255\n"),
+		255, 0, 0, 0, 128, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 255,
+	)
+	assert.Equal(t, expected, buf.Bytes())
+}
+
+/*
+	Scenario: Reading an ascii PPM with comments
+	Given ppm ← a file containing
+		"""
+		P3
+		# made by hand
+		2 1 # width and height
+		255
+		255 0 51 # first pixel
+		0 102 255
+		"""
+	When c ← ppm_to_canvas(ppm)
+	Then c.width = 2
+	And c.height = 1
+	And pixel_at(c, 0, 0) = color(1, 0, 0.2)
+	And pixel_at(c, 1, 0) = color(0, 0.4, 1)
+*/
+func TestParsePPMAscii(t *testing.T) {
+	ppm := "P3\n# made by hand\n2 1 # width and height\n255\n255 0 51 # first pixel\n0 102 255\n"
+	c, err := ParsePPM(strings.NewReader(ppm))
+	require.Nil(t, err)
+	assert.Equal(t, 2, c.Width)
+	assert.Equal(t, 1, c.Height)
+	assert.True(t, c.PixelAt(0, 0).Equal(Color{1, 0, 0.2}))
+	assert.True(t, c.PixelAt(1, 0).Equal(Color{0, 0.4, 1}))
+}
+
+/*
+	Scenario Outline: Reading a PPM scales by its maxval
+	Given ppm ← a file containing
+		"""
+		P3
+		1 1
+		<maxval>
+		<red> <green> <blue>
+		"""
+	When c ← ppm_to_canvas(ppm)
+	Then pixel_at(c, 0, 0) = <color>
+
+	Examples:
+		| maxval | red   | green | blue  | color                   |
+		| 1      | 1     | 0     | 1     | color(1, 0, 1)          |
+		| 100    | 25    | 50    | 100   | color(0.25, 0.5, 1)     |
+		| 65535  | 65535 | 0     | 32768 | color(1, 0, 0.50000763) |
+*/
+func TestParsePPMMaxval(t *testing.T) {
+	examples := []struct {
+		ppm   string
+		color Color
+	}{
+		{"P3\n1 1\n1\n1 0 1\n", Color{1, 0, 1}},
+		{"P3\n1 1\n100\n25 50 100\n", Color{0.25, 0.5, 1}},
+		{"P3\n1 1\n65535\n65535 0 32768\n", Color{1, 0, 32768.0 / 65535}},
+	}
+	for _, e := range examples {
+		c, err := ParsePPM(strings.NewReader(e.ppm))
+		require.Nil(t, err)
+		assert.True(t, c.PixelAt(0, 0).Equal(e.color), "%q", e.ppm)
+	}
+}
+
+/*
+	Scenario: Reading a binary PPM with comments in the header
+	Given ppm ← "P6\n# comment\n2 1\n255\n" followed by bytes 255 0 51 0 102 255
+	When c ← ppm_to_canvas(ppm)
+	Then pixel_at(c, 0, 0) = color(1, 0, 0.2)
+	And pixel_at(c, 1, 0) = color(0, 0.4, 1)
+*/
+func TestParsePPMBinary(t *testing.T) {
+	ppm := append([]byte("P6\n# comment\n2 1\n255\n"), 255, 0, 51, 0, 102, 255)
+	c, err := ParsePPM(bytes.NewReader(ppm))
+	require.Nil(t, err)
+	assert.True(t, c.PixelAt(0, 0).Equal(Color{1, 0, 0.2}))
+	assert.True(t, c.PixelAt(1, 0).Equal(Color{0, 0.4, 1}))
+}
+
+/*
+	Scenario: Reading a 16 bit binary PPM
+	Given ppm ← "P6 1 1 1000\n" followed by big endian words 1000 500 0
+	When c ← ppm_to_canvas(ppm)
+	Then pixel_at(c, 0, 0) = color(1, 0.5, 0)
+*/
+func TestParsePPMBinary16Bit(t *testing.T) {
+	ppm := append([]byte("P6 1 1 1000\n"), 0x03, 0xe8, 0x01, 0xf4, 0x00, 0x00)
+	c, err := ParsePPM(bytes.NewReader(ppm))
+	require.Nil(t, err)
+	assert.True(t, c.PixelAt(0, 0).Equal(Color{1, 0.5, 0}))
+}
+
+/*
+	Scenario Outline: Reading an invalid PPM is an error
+	Given ppm ← <ppm>
+	Then ppm_to_canvas(ppm) is an error
+
+	Examples:
+		| ppm                         | reason                |
+		| "P5\n1 1\n255\n0"           | unsupported format    |
+		| "P3\n1\n"                   | missing height        |
+		| "P3\nx 1\n255\n0 0 0\n"     | invalid width         |
+		| "P3\n1 1\n0\n0 0 0\n"       | maxval out of range   |
+		| "P3\n1 1\n255\n0 0\n"       | missing channel       |
+		| "P3\n1 1\n255\n0 256 0\n"   | channel above maxval  |
+		| "P6\n2 1\n255\n\x00\x00\x00"| truncated raster      |
+		| "P6\n100000 100000\n255\n"  | too large to allocate |
+		| "P6\n-2 -3\n255\n"          | negative size         |
+*/
+func TestParsePPMInvalid(t *testing.T) {
+	examples := []string{
+		"P5\n1 1\n255\n0",
+		"P3\n1\n",
+		"P3\nx 1\n255\n0 0 0\n",
+		"P3\n1 1\n0\n0 0 0\n",
+		"P3\n1 1\n255\n0 0\n",
+		"P3\n1 1\n255\n0 256 0\n",
+		"P6\n2 1\n255\n\x00\x00\x00",
+		"P6\n100000 100000\n255\n",
+		"P6\n-2 -3\n255\n",
+	}
+	for _, ppm := range examples {
+		_, err := ParsePPM(strings.NewReader(ppm))
+		assert.NotNil(t, err, "%q", ppm)
+	}
+}
+
+/*
+	Scenario Outline: A canvas round trips through a PPM file
+	Given c ← canvas(10, 7) with every pixel a different color
+	When c is written as <format> to a file
+	And c2 ← the file read back
+	Then every pixel of c2 is within 1/255 of c
+	And writing c2 as <format> gives the same file
+
+	Examples:
+		| format |
+		| P3     |
+		| P6     |
+*/
+func TestPPMRoundTrip(t *testing.T) {
+	fn := "test.ppm"
+	c := NewCanvas(10, 7)
+	for x := 0; x < c.Width; x++ {
+		for y := 0; y < c.Height; y++ {
+			c.WritePixel(x, y, Color{float64(x) / 9, float64(y) / 6, float64(x*y) / 54})
+		}
+	}
+
+	examples := []func(Canvas) error{
+		func(c Canvas) error { return c.ToPPM(fn) },
+		func(c Canvas) error { return c.ToPPMBinary(fn) },
+	}
+	for _, write := range examples {
+		require.Nil(t, write(c))
+		written, err := ioutil.ReadFile(fn)
+		require.Nil(t, err)
+
+		c2, err := LoadPPM(fn)
+		require.Nil(t, err)
+		require.Equal(t, c.Width, c2.Width)
+		require.Equal(t, c.Height, c2.Height)
+		for x := 0; x < c.Width; x++ {
+			for y := 0; y < c.Height; y++ {
+				expected, actual := c.PixelAt(x, y), c2.PixelAt(x, y)
+				assert.InDeltaSlice(t,
+					[]float64{expected.Red, expected.Green, expected.Blue},
+					[]float64{actual.Red, actual.Green, actual.Blue},
+					1.0/255,
+				)
+			}
+		}
+
+		require.Nil(t, write(c2))
+		rewritten, err := ioutil.ReadFile(fn)
+		require.Nil(t, err)
+		assert.Equal(t, written, rewritten)
+	}
+	require.Nil(t, os.Remove(fn))
+}
+
+/*
+	Scenario: Loading a missing PPM file
+	Then load_ppm("missing.ppm") is an error
+*/
+func TestLoadPPMMissing(t *testing.T) {
+	_, err := LoadPPM("missing.ppm")
+	assert.NotNil(t, err)
+}
+
+/*
+	Scenario: The image size limit is checked before the canvas is allocated
+	Given max_image_pixels ← 6
+	Then parse_ppm("P3\n3 2\n255\n" + 6 black pixels) succeeds
+	And parse_ppm("P3\n4 2\n255\n") fails with "image of 4 x 2 is larger than the limit of 6 pixels"
+*/
+func TestParsePPMSizeLimit(t *testing.T) {
+	defer func(old int) { MaxImagePixels = old }(MaxImagePixels)
+	MaxImagePixels = 6
+
+	_, err := ParsePPM(strings.NewReader("P3\n3 2\n255\n" + strings.Repeat("0 0 0\n", 6)))
+	assert.Nil(t, err)
+	_, err = ParsePPM(strings.NewReader("P3\n4 2\n255\n"))
+	require.NotNil(t, err)
+	assert.Equal(t, "image of 4 x 2 is larger than the limit of 6 pixels", err.Error())
+}
@@ -9,9 +9,9 @@ func (r Ray) Position(t float64) Tuple {
 	return r.Origin.Add(r.Direction.Mul(t))
 }
 
-func (r Ray) Transform(m Matrix) Ray {
-	o := m.MustMulT(r.Origin)
-	d := m.MustMulT(r.Direction)
+func (r Ray) Transform(m Mat4) Ray {
+	o := m.MulT(r.Origin)
+	d := m.MulT(r.Direction)
 	return Ray{o, d}
 }
 
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// Renderer - renders a camera's view of a world in square tiles, shaded on a
+// pool of goroutines. Every pixel is shaded exactly as Camera.Render would,
+// so the result is identical to a serial render whatever the tile size or
+// number of workers
+type Renderer struct {
+	Camera Camera
+	World  World
+
+	// TileSize - width and height of a tile in pixels, edge tiles may be smaller
+	TileSize int
+	// Workers - number of goroutines shading tiles
+	Workers int
+
+	// Progress - optional, receives an event each time a tile is finished.
+	// Sends block until the event is read or the render is cancelled. The
+	// channel belongs to the caller, Render never closes it, so one channel
+	// can follow several renders. Every event is sent before Render returns
+	Progress chan<- Progress
+}
+
+// Progress - how far along a render is
+type Progress struct {
+	TilesDone   int
+	TilesTotal  int
+	PixelsDone  int
+	PixelsTotal int
+}
+
+// Fraction - portion of the pixels rendered so far, between 0 and 1
+func (p Progress) Fraction() float64 {
+	if p.PixelsTotal == 0 {
+		return 1
+	}
+	return float64(p.PixelsDone) / float64(p.PixelsTotal)
+}
+
+// tile - pixels from x0, y0 up to but not including x1, y1
+type tile struct {
+	x0, y0 int
+	x1, y1 int
+}
+
+func (t tile) pixels() int {
+	return (t.x1 - t.x0) * (t.y1 - t.y0)
+}
+
+// NewRenderer - renderer with 32 pixel tiles and a worker per cpu
+func NewRenderer(c Camera, w World) *Renderer {
+	return &Renderer{
+		Camera:   c,
+		World:    w,
+		TileSize: 32,
+		Workers:  runtime.NumCPU(),
+	}
+}
+
+// tiles - tiles covering the canvas, left to right and top to bottom
+func (r *Renderer) tiles() []tile {
+	size := r.TileSize
+	if size < 1 {
+		size = 1
+	}
+	tiles := []tile{}
+	for y := 0; y < r.Camera.VSize; y += size {
+		for x := 0; x < r.Camera.HSize; x += size {
+			t := tile{x, y, x + size, y + size}
+			if t.x1 > r.Camera.HSize {
+				t.x1 = r.Camera.HSize
+			}
+			if t.y1 > r.Camera.VSize {
+				t.y1 = r.Camera.VSize
+			}
+			tiles = append(tiles, t)
+		}
+	}
+	return tiles
+}
+
+// renderTile - shade every pixel of a tile, false if ctx was cancelled part way
+func (r *Renderer) renderTile(ctx context.Context, image *Canvas, t tile) bool {
+	for y := t.y0; y < t.y1; y++ {
+		if ctx.Err() != nil {
+			return false
+		}
+		for x := t.x0; x < t.x1; x++ {
+			image.WritePixel(x, y, r.World.ColorAt(r.Camera.RayForPixel(x, y)))
+		}
+	}
+	return true
+}
+
+// Render - render the world, returning early with ctx's error if it is
+// cancelled. The canvas is still returned then, with the unfinished tiles black
+func (r *Renderer) Render(ctx context.Context) (Canvas, error) {
+	image := NewCanvas(r.Camera.HSize, r.Camera.VSize)
+	tiles := r.tiles()
+
+	workers := r.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	// feed tiles to the workers until they run out or the render is cancelled
+	jobs := make(chan tile)
+	go func() {
+		defer close(jobs)
+		for _, t := range tiles {
+			select {
+			case jobs <- t:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// each worker writes only the pixels of its own tiles, so they never
+	// touch the same memory
+	done := make(chan tile)
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				if r.renderTile(ctx, &image, t) {
+					done <- t
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	progress := Progress{TilesTotal: len(tiles), PixelsTotal: r.Camera.HSize * r.Camera.VSize}
+	for t := range done {
+		progress.TilesDone++
+		progress.PixelsDone += t.pixels()
+		if r.Progress != nil {
+			select {
+			case r.Progress <- progress:
+			case <-ctx.Done():
+			}
+		}
+	}
+
+	if progress.TilesDone < progress.TilesTotal {
+		return image, ctx.Err()
+	}
+	return image, nil
+}
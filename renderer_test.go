@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rendererScene - default world plus a floor and a divided group of spheres,
+// so workers share every kind of container while they render
+func rendererScene(hsize, vsize int) (Camera, World) {
+	w := defaultWorld()
+	w.Objects = append(w.Objects, NewPlane().WithTransform(NewTranslation(0, -1, 0)))
+	g := NewGroup()
+	g.AddChild(randomSpheres(50, 4)...)
+	g.Divide(4)
+	g.SetTransform(NewScaling(0.2, 0.2, 0.2).Translate(0, 0, 3))
+	w.Objects = append(w.Objects, g)
+
+	c := NewCamera(hsize, vsize, math.Pi/2).WithTransform(ViewTransform(
+		NewPoint(0, 1, -5),
+		NewPoint(0, 0, 0),
+		NewVector(0, 1, 0),
+	))
+	return c, w
+}
+
+/*
+	Scenario: Constructing a renderer
+	Given c ← camera(160, 120, π/2)
+	And w ← default_world()
+	When r ← renderer(c, w)
+	Then r.tile_size = 32
+	And r.workers = number of cpus
+*/
+func TestCreateRenderer(t *testing.T) {
+	c := NewCamera(160, 120, math.Pi/2)
+	r := NewRenderer(c, defaultWorld())
+	assert.Equal(t, 32, r.TileSize)
+	assert.True(t, r.Workers >= 1)
+	assert.Nil(t, r.Progress)
+}
+
+/*
+	Scenario: Tiles cover every pixel exactly once
+	Given c ← camera(70, 45, π/2)
+	And r ← renderer(c, world()) with tile_size 16
+	When tiles ← tiles(r)
+	Then tiles.count = 15
+	And every pixel is in exactly one tile
+*/
+func TestRendererTilesCoverCanvas(t *testing.T) {
+	r := NewRenderer(NewCamera(70, 45, math.Pi/2), NewWorld())
+	r.TileSize = 16
+	tiles := r.tiles()
+	assert.Equal(t, 15, len(tiles))
+
+	covered := map[[2]int]int{}
+	total := 0
+	for _, tl := range tiles {
+		total += tl.pixels()
+		for y := tl.y0; y < tl.y1; y++ {
+			for x := tl.x0; x < tl.x1; x++ {
+				covered[[2]int{x, y}]++
+			}
+		}
+	}
+	assert.Equal(t, 70*45, total)
+	assert.Equal(t, 70*45, len(covered))
+	for _, n := range covered {
+		assert.Equal(t, 1, n)
+	}
+}
+
+/*
+	Scenario Outline: A parallel render matches a serial render
+	Given (c, w) ← a world with a floor and a divided group
+	And r ← renderer(c, w) with <workers> workers and tile_size <tile_size>
+	When image ← render(r)
+	Then image = render(c, w)
+
+	Examples:
+		| workers | tile_size |
+		| 1       | 32        |
+		| 4       | 7         |
+		| 8       | 1         |
+		| 3       | 100       |
+*/
+func TestRendererMatchesSerialRender(t *testing.T) {
+	c, w := rendererScene(41, 23)
+	expected := c.Render(w)
+
+	examples := []struct {
+		workers  int
+		tileSize int
+	}{
+		{1, 32},
+		{4, 7},
+		{8, 1},
+		{3, 100},
+	}
+	for _, e := range examples {
+		r := NewRenderer(c, w)
+		r.Workers = e.workers
+		r.TileSize = e.tileSize
+		image, err := r.Render(context.Background())
+		require.Nil(t, err)
+		assert.Equal(t, expected, image, "workers %v, tile size %v", e.workers, e.tileSize)
+	}
+}
+
+/*
+	Scenario: Many workers render a shared world at once
+	Given (c, w) ← a world with a floor and a divided group
+	And r ← renderer(c, w) with 16 workers and tile_size 4
+	When image ← render(r)
+	Then image = render(c, w)
+	# run with go test -race, the workers must not share any writes
+*/
+func TestRendererRace(t *testing.T) {
+	c, w := rendererScene(64, 48)
+	r := NewRenderer(c, w)
+	r.Workers = 16
+	r.TileSize = 4
+	progress := make(chan Progress)
+	r.Progress = progress
+	go func() {
+		for range progress {
+		}
+	}()
+	image, err := r.Render(context.Background())
+	close(progress)
+	require.Nil(t, err)
+	assert.Equal(t, c.Render(w), image)
+}
+
+/*
+	Scenario: A render sends progress for every tile
+	Given c ← camera(20, 10, π/2)
+	And r ← renderer(c, default_world()) with tile_size 5
+	When image ← render(r)
+	Then r sends 8 progress events
+	And the last event has every tile and pixel done
+*/
+func TestRendererProgress(t *testing.T) {
+	r := NewRenderer(NewCamera(20, 10, math.Pi/2), defaultWorld())
+	r.TileSize = 5
+	progress := make(chan Progress)
+	r.Progress = progress
+
+	events := make(chan []Progress)
+	go func() {
+		received := []Progress{}
+		for p := range progress {
+			received = append(received, p)
+		}
+		events <- received
+	}()
+
+	_, err := r.Render(context.Background())
+	close(progress)
+	require.Nil(t, err)
+	received := <-events
+
+	require.Equal(t, 8, len(received))
+	for i, p := range received {
+		assert.Equal(t, i+1, p.TilesDone)
+		assert.Equal(t, 8, p.TilesTotal)
+		assert.Equal(t, 200, p.PixelsTotal)
+	}
+	last := received[len(received)-1]
+	assert.Equal(t, 200, last.PixelsDone)
+	assert.Equal(t, 1.0, last.Fraction())
+}
+
+/*
+	Scenario: Rendering twice with the same progress channel
+	Given c ← camera(20, 10, π/2)
+	And r ← renderer(c, default_world()) with tile_size 5
+	When image1 ← render(r)
+	And image2 ← render(r)
+	Then image1 = image2
+	And r sends 8 progress events for each render
+*/
+func TestRendererRenderTwice(t *testing.T) {
+	r := NewRenderer(NewCamera(20, 10, math.Pi/2), defaultWorld())
+	r.TileSize = 5
+	progress := make(chan Progress)
+	r.Progress = progress
+
+	events := make(chan int)
+	go func() {
+		count := 0
+		for range progress {
+			count++
+		}
+		events <- count
+	}()
+
+	image1, err := r.Render(context.Background())
+	require.Nil(t, err)
+	image2, err := r.Render(context.Background())
+	require.Nil(t, err)
+	close(progress)
+
+	assert.Equal(t, image1, image2)
+	assert.Equal(t, 16, <-events)
+}
+
+/*
+	Scenario: A cancelled render stops with the context's error
+	Given c ← camera(50, 50, π/2)
+	And ctx is already cancelled
+	When (image, err) ← render(renderer(c, default_world()), ctx)
+	Then err = context canceled
+	And image is 50 x 50 and black
+*/
+func TestRendererCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := NewRenderer(NewCamera(50, 50, math.Pi/2), defaultWorld())
+	image, err := r.Render(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, NewCanvas(50, 50), image)
+}
+
+/*
+	Scenario: Cancelling a render part way through
+	Given c ← camera(100, 100, π/2)
+	And r ← renderer(c, default_world()) with tile_size 10 and 2 workers
+	When the render is cancelled after the first progress event
+	Then err = context canceled
+	And fewer than all tiles were reported
+*/
+func TestRendererCancelledPartWay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := NewRenderer(NewCamera(100, 100, math.Pi/2), defaultWorld())
+	r.TileSize = 10
+	r.Workers = 2
+	progress := make(chan Progress)
+	r.Progress = progress
+
+	last := make(chan Progress)
+	go func() {
+		var p Progress
+		for e := range progress {
+			if e.TilesDone == 1 {
+				cancel()
+			}
+			p = e
+		}
+		last <- p
+	}()
+
+	_, err := r.Render(ctx)
+	close(progress)
+	assert.Equal(t, context.Canceled, err)
+	p := <-last
+	assert.True(t, p.TilesDone < p.TilesTotal)
+}
@@ -0,0 +1,799 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Scene - camera and world described by a scene file
+type Scene struct {
+	Camera Camera
+	World  World
+}
+
+// LoadScene - parse the yaml scene file with the given name
+func LoadScene(fn string) (*Scene, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scene, err := ParseScene(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", fn, err)
+	}
+	return scene, nil
+}
+
+// ParseScene - parse a yaml scene in the style of The Ray Tracer Challenge.
+// The document is a list of items, each one of
+//
+//	add: camera     width, height, field-of-view, from, to, up
+//	add: light      at, intensity
+//	add: <shape>    material, transform, plus min, max and closed for
+//	                cylinders and cones. shapes are sphere, plane, cube,
+//	                cylinder and cone
+//	define: <name>  value, and optionally extend: <name> to start from
+//	                another material definition
+//
+// Materials are a map of material fields or the name of a definition, their
+// pattern a map of type, colors and transform.
+// Transforms are a list of [translate, x, y, z], [scale, x, y, z],
+// [rotate-x, radians] and so on, or names of definitions holding such lists,
+// applied in the order they are listed
+func ParseScene(r io.Reader) (*Scene, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &items); err != nil {
+		// yaml's own errors already carry the line
+		return nil, err
+	}
+
+	p := &sceneParser{
+		lines:      strings.Split(string(data), "\n"),
+		defines:    map[string]interface{}{},
+		defineLocs: map[string]sceneLoc{},
+		expanding:  map[string]bool{},
+		scene:      &Scene{World: NewWorld()},
+	}
+	p.findItems(len(items))
+
+	for i, item := range items {
+		if err := p.parseItem(p.itemLoc(i), item); err != nil {
+			return nil, err
+		}
+	}
+	if !p.hasCamera {
+		return nil, fmt.Errorf("scene has no camera")
+	}
+	return p.scene, nil
+}
+
+// sceneParser - state built up while going through the items of a scene
+type sceneParser struct {
+	// lines of the source, and the line each top level item starts on.
+	// yaml.v2 doesn't keep positions, so they are found in the text
+	lines []string
+	items []int
+
+	defines map[string]interface{}
+	// defineLocs - where the value of each define is, for errors found while using it
+	defineLocs map[string]sceneLoc
+	// expanding - transform defines being expanded, to catch ones that refer to themselves
+	expanding map[string]bool
+	scene     *Scene
+	hasCamera bool
+}
+
+// sceneError - error at a line of the scene
+type sceneError struct {
+	line int
+	msg  string
+}
+
+func (e sceneError) Error() string {
+	if e.line == 0 {
+		return e.msg
+	}
+	return fmt.Sprintf("line %v: %v", e.line, e.msg)
+}
+
+// sceneLoc - where a value is in the source. line holds its key, or the "-"
+// of a list entry, and start to end are the lines of its block, empty when
+// the value is written inline. line is 0 when the position isn't known
+type sceneLoc struct {
+	line       int
+	start, end int
+}
+
+var sceneItemStart = regexp.MustCompile(`^( *)-(\s|$)`)
+
+// findItems - record the line of every "- " of the top level list, which
+// may be indented. Lines that don't match the count of parsed items would
+// point errors at the wrong places, so then no positions are kept
+func (p *sceneParser) findItems(count int) {
+	indent := -1
+	for i, line := range p.lines {
+		if indent < 0 {
+			_, content := lineContent(line)
+			if isBlankLine(content) || content == "---" || strings.HasPrefix(content, "%") {
+				continue
+			}
+			m := sceneItemStart.FindStringSubmatch(line)
+			if m == nil {
+				return
+			}
+			indent = len(m[1])
+		}
+		if m := sceneItemStart.FindStringSubmatch(line); m != nil && len(m[1]) == indent {
+			p.items = append(p.items, i+1)
+		}
+	}
+	if len(p.items) != count {
+		p.items = nil
+	}
+}
+
+// itemLoc - location of top level item i
+func (p *sceneParser) itemLoc(i int) sceneLoc {
+	if i >= len(p.items) {
+		return sceneLoc{}
+	}
+	end := len(p.lines)
+	if i+1 < len(p.items) {
+		end = p.items[i+1] - 1
+	}
+	return sceneLoc{p.items[i], p.items[i], end}
+}
+
+// lineContent - column the content of a line starts at, past its indent and
+// any "- " list markers, and the content
+func lineContent(line string) (int, string) {
+	col := 0
+	for {
+		trimmed := strings.TrimLeft(line, " ")
+		col += len(line) - len(trimmed)
+		line = trimmed
+		if line == "-" {
+			return col + 1, ""
+		}
+		if !strings.HasPrefix(line, "- ") {
+			return col, line
+		}
+		col++
+		line = line[1:]
+	}
+}
+
+func isBlankLine(content string) bool {
+	return content == "" || strings.HasPrefix(content, "#")
+}
+
+// findKey - line of key in the map at loc, 0 if it isn't there. Only keys at
+// the map's own indent count, not ones of the same name nested further in
+func (p *sceneParser) findKey(loc sceneLoc, key string) int {
+	indent := -1
+	pattern := regexp.MustCompile(`^` + regexp.QuoteMeta(key) + `\s*:(\s|$)`)
+	for l := loc.start; l >= 1 && l <= loc.end; l++ {
+		col, content := lineContent(p.lines[l-1])
+		if isBlankLine(content) {
+			continue
+		}
+		if indent < 0 {
+			indent = col
+		}
+		if col == indent && pattern.MatchString(content) {
+			return l
+		}
+	}
+	return 0
+}
+
+// at - location of the value of key in the map at loc. When the key can't
+// be found the value is placed at loc itself
+func (p *sceneParser) at(loc sceneLoc, key string) sceneLoc {
+	l := p.findKey(loc, key)
+	if l == 0 {
+		return sceneLoc{loc.line, loc.line + 1, loc.line}
+	}
+	col, content := lineContent(p.lines[l-1])
+	value := strings.TrimSpace(content[strings.Index(content, ":")+1:])
+	if !isBlankLine(value) {
+		return sceneLoc{l, l + 1, l}
+	}
+
+	// the block runs while lines are indented past the key, a list may also
+	// start at the key's own indent
+	end := l
+	for n := l + 1; n <= loc.end; n++ {
+		line := p.lines[n-1]
+		c, content := lineContent(line)
+		if isBlankLine(content) {
+			continue
+		}
+		lead := len(line) - len(strings.TrimLeft(line, " "))
+		if lead < col || (lead == col && c == col) {
+			break
+		}
+		end = n
+	}
+	return sceneLoc{l, l + 1, end}
+}
+
+// elem - location of entry n of the list at loc. Inline lists have no lines
+// of their own, so their entries are placed at loc
+func (p *sceneParser) elem(loc sceneLoc, n int) sceneLoc {
+	found := sceneLoc{loc.line, loc.line + 1, loc.line}
+	dash, count := -1, -1
+	for l := loc.start; l >= 1 && l <= loc.end; l++ {
+		line := p.lines[l-1]
+		_, content := lineContent(line)
+		if isBlankLine(content) && strings.TrimSpace(line) != "-" {
+			continue
+		}
+		m := sceneItemStart.FindStringSubmatch(line)
+		if dash < 0 {
+			if m == nil {
+				return found
+			}
+			dash = len(m[1])
+		}
+		if m == nil || len(m[1]) != dash {
+			continue
+		}
+		count++
+		if count == n {
+			found = sceneLoc{l, l, loc.end}
+		} else if count == n+1 {
+			found.end = l - 1
+			break
+		}
+	}
+	return found
+}
+
+// errorf - error positioned at key in the map at loc, or at loc itself when
+// there is no key or it can't be found
+func (p *sceneParser) errorf(loc sceneLoc, key string, format string, args ...interface{}) error {
+	line := loc.line
+	if key != "" {
+		if l := p.findKey(loc, key); l != 0 {
+			line = l
+		}
+	}
+	return sceneError{line, fmt.Sprintf(format, args...)}
+}
+
+func (p *sceneParser) parseItem(loc sceneLoc, item map[string]interface{}) error {
+	if name, ok := item["define"]; ok {
+		return p.parseDefine(loc, name, item)
+	}
+	kind, ok := item["add"].(string)
+	if !ok {
+		return p.errorf(loc, "", "item must have an add or define key")
+	}
+
+	switch kind {
+	case "camera":
+		return p.parseCamera(loc, item)
+	case "light":
+		return p.parseLight(loc, item)
+	}
+	s, err := p.parseShape(loc, kind, item)
+	if err != nil {
+		return err
+	}
+	p.scene.World.Objects = append(p.scene.World.Objects, s)
+	return nil
+}
+
+func (p *sceneParser) parseDefine(loc sceneLoc, name interface{}, item map[string]interface{}) error {
+	if err := p.checkKeys(loc, item, "define", "value", "extend"); err != nil {
+		return err
+	}
+	n, ok := name.(string)
+	if !ok || n == "" {
+		return p.errorf(loc, "define", "define needs a name")
+	}
+	value, ok := item["value"]
+	if !ok {
+		return p.errorf(loc, "define", "define %q has no value", n)
+	}
+
+	if parent, ok := item["extend"]; ok {
+		pn, _ := parent.(string)
+		base, ok := p.defines[pn].(map[interface{}]interface{})
+		if !ok {
+			return p.errorf(loc, "extend", "cannot extend %v, it is not a defined material", parent)
+		}
+		fields, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return p.errorf(loc, "value", "an extended define must have a map value")
+		}
+		merged := map[interface{}]interface{}{}
+		for k, v := range base {
+			merged[k] = v
+		}
+		for k, v := range fields {
+			merged[k] = v
+		}
+		value = merged
+	}
+	p.defines[n] = value
+	p.defineLocs[n] = p.at(loc, "value")
+	return nil
+}
+
+func (p *sceneParser) parseCamera(loc sceneLoc, item map[string]interface{}) error {
+	if err := p.checkKeys(loc, item, "add", "width", "height", "field-of-view", "from", "to", "up"); err != nil {
+		return err
+	}
+	width, err := p.intField(loc, item, "width")
+	if err != nil {
+		return err
+	}
+	height, err := p.intField(loc, item, "height")
+	if err != nil {
+		return err
+	}
+	if width < 1 || height < 1 {
+		return p.errorf(loc, "width", "camera size must be positive, got %v x %v", width, height)
+	}
+	fov, err := p.floatField(loc, item, "field-of-view")
+	if err != nil {
+		return err
+	}
+	// at 0 every pixel gets the same ray, from π on the view flips over
+	if fov <= 0 || fov >= math.Pi {
+		return p.errorf(loc, "field-of-view", "field-of-view must be between 0 and π radians, got %v", fov)
+	}
+	from, err := p.tripleField(loc, item, "from")
+	if err != nil {
+		return err
+	}
+	to, err := p.tripleField(loc, item, "to")
+	if err != nil {
+		return err
+	}
+	up, err := p.tripleField(loc, item, "up")
+	if err != nil {
+		return err
+	}
+
+	// ViewTransform needs a direction to look in and an up that isn't along it
+	forward := NewPoint(to[0], to[1], to[2]).Sub(NewPoint(from[0], from[1], from[2]))
+	if forward.Mag() < epsilon {
+		return p.errorf(loc, "to", "camera looks from and to the same point %v", to)
+	}
+	if forward.Cross(NewVector(up[0], up[1], up[2])).Mag() < epsilon {
+		return p.errorf(loc, "up", "camera up %v is zero or parallel to the view direction", up)
+	}
+
+	p.scene.Camera = NewCamera(width, height, fov).WithTransform(ViewTransform(
+		NewPoint(from[0], from[1], from[2]),
+		NewPoint(to[0], to[1], to[2]),
+		NewVector(up[0], up[1], up[2]),
+	))
+	p.hasCamera = true
+	return nil
+}
+
+func (p *sceneParser) parseLight(loc sceneLoc, item map[string]interface{}) error {
+	if err := p.checkKeys(loc, item, "add", "at", "intensity"); err != nil {
+		return err
+	}
+	at, err := p.tripleField(loc, item, "at")
+	if err != nil {
+		return err
+	}
+	intensity, err := p.tripleField(loc, item, "intensity")
+	if err != nil {
+		return err
+	}
+	p.scene.World.Lights = append(p.scene.World.Lights, PointLight{
+		NewPoint(at[0], at[1], at[2]),
+		Color{intensity[0], intensity[1], intensity[2]},
+	})
+	return nil
+}
+
+func (p *sceneParser) parseShape(loc sceneLoc, kind string, item map[string]interface{}) (Shape, error) {
+	keys := []string{"add", "material", "transform"}
+	var s Shape
+	switch kind {
+	case "sphere":
+		s = NewSphere()
+	case "plane":
+		s = NewPlane()
+	case "cube":
+		s = NewCube()
+	case "cylinder", "cone":
+		keys = append(keys, "min", "max", "closed")
+		min, max, closed, err := p.parseTruncation(loc, item)
+		if err != nil {
+			return nil, err
+		}
+		if kind == "cylinder" {
+			c := NewCylinder()
+			c.SetMinimum(min)
+			c.SetMaximum(max)
+			c.Closed = closed
+			s = c
+		} else {
+			c := NewCone()
+			c.SetMinimum(min)
+			c.SetMaximum(max)
+			c.Closed = closed
+			s = c
+		}
+	default:
+		return nil, p.errorf(loc, "add", "unknown item %q", kind)
+	}
+	if err := p.checkKeys(loc, item, keys...); err != nil {
+		return nil, err
+	}
+
+	if v, ok := item["material"]; ok {
+		m, err := p.parseMaterial(p.at(loc, "material"), v)
+		if err != nil {
+			return nil, err
+		}
+		s.SetMaterial(m)
+	}
+	if v, ok := item["transform"]; ok {
+		tloc := p.at(loc, "transform")
+		t, err := p.parseTransform(tloc, v)
+		if err != nil {
+			return nil, err
+		}
+		s.SetTransform(t)
+		if _, err := s.Inverse(); err != nil {
+			return nil, p.errorf(tloc, "", "transform can't be inverted")
+		}
+	}
+	return s, nil
+}
+
+// parseTruncation - min, max and closed of a cylinder or cone, infinite and open by default
+func (p *sceneParser) parseTruncation(loc sceneLoc, item map[string]interface{}) (min, max float64, closed bool, err error) {
+	c := NewCylinder()
+	min, max = c.Minimum(), c.Maximum()
+	if _, ok := item["min"]; ok {
+		if min, err = p.floatField(loc, item, "min"); err != nil {
+			return
+		}
+	}
+	if _, ok := item["max"]; ok {
+		if max, err = p.floatField(loc, item, "max"); err != nil {
+			return
+		}
+	}
+	if v, ok := item["closed"]; ok {
+		if closed, ok = v.(bool); !ok {
+			err = p.errorf(loc, "closed", "closed must be true or false, got %v", v)
+		}
+	}
+	return
+}
+
+// parseMaterial - material from a map of fields or the name of a definition.
+// Fields not given keep the default material's values
+func (p *sceneParser) parseMaterial(loc sceneLoc, v interface{}) (Material, error) {
+	if name, ok := v.(string); ok {
+		d, ok := p.defines[name]
+		if !ok {
+			return Material{}, p.errorf(loc, "", "undefined material %q", name)
+		}
+		v, loc = d, p.defineLocs[name]
+	}
+	fields, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return Material{}, p.errorf(loc, "", "material must be a map or the name of a define")
+	}
+
+	m := NewMaterial()
+	targets := map[string]*float64{
+		"ambient":          &m.Ambient,
+		"diffuse":          &m.Diffuse,
+		"specular":         &m.Specular,
+		"shininess":        &m.Shininess,
+		"reflective":       &m.Reflective,
+		"transparency":     &m.Transparency,
+		"refractive-index": &m.RefractiveIndex,
+	}
+	for _, key := range sortedKeys(fields) {
+		value := fields[key]
+		if key == "color" {
+			c, ok := toTriple(value)
+			if !ok {
+				return Material{}, p.errorf(loc, "color", "color must be a list of 3 numbers, got %v", value)
+			}
+			m.Color = Color{c[0], c[1], c[2]}
+			continue
+		}
+		if key == "pattern" {
+			pat, err := p.parsePattern(p.at(loc, "pattern"), value)
+			if err != nil {
+				return Material{}, err
+			}
+			m.Pattern = pat
+			continue
+		}
+		target, ok := targets[key]
+		if !ok {
+			return Material{}, p.errorf(loc, key, "unknown material field %q", key)
+		}
+		if *target, ok = toFloat(value); !ok {
+			return Material{}, p.errorf(loc, key, "%v must be a number, got %v", key, value)
+		}
+	}
+	return m, nil
+}
+
+// parsePattern - pattern from a map with a type and an optional transform.
+// stripes, gradient, rings, checkers and blend take 2 colors, each a list of
+// 3 numbers or a nested pattern map. perturbed takes a single pattern and
+// the scale of its noise
+func (p *sceneParser) parsePattern(loc sceneLoc, v interface{}) (Pattern, error) {
+	fields, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return nil, p.errorf(loc, "", "pattern must be a map")
+	}
+
+	keys := []string{"type", "colors", "transform"}
+	var pat Pattern
+	switch fields["type"] {
+	case "stripes", "gradient", "rings", "checkers", "blend":
+		list, ok := fields["colors"].([]interface{})
+		if !ok || len(list) != 2 {
+			return nil, p.errorf(loc, "colors", "%v pattern needs a list of 2 colors", fields["type"])
+		}
+		colors := p.at(loc, "colors")
+		a, err := p.parsePatternColor(p.elem(colors, 0), list[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := p.parsePatternColor(p.elem(colors, 1), list[1])
+		if err != nil {
+			return nil, err
+		}
+		switch fields["type"] {
+		case "stripes":
+			pat = NewStripePattern(a, b)
+		case "gradient":
+			pat = NewGradientPattern(a, b)
+		case "rings":
+			pat = NewRingPattern(a, b)
+		case "checkers":
+			pat = NewCheckerPattern(a, b)
+		case "blend":
+			pat = NewBlendPattern(a, b)
+		}
+	case "perturbed":
+		keys = []string{"type", "pattern", "scale", "transform"}
+		inner, ok := fields["pattern"]
+		if !ok {
+			return nil, p.errorf(loc, "type", "perturbed pattern needs a pattern")
+		}
+		child, err := p.parsePattern(p.at(loc, "pattern"), inner)
+		if err != nil {
+			return nil, err
+		}
+		scale, ok := toFloat(fields["scale"])
+		if !ok {
+			key := "scale"
+			if _, given := fields["scale"]; !given {
+				key = "type"
+			}
+			return nil, p.errorf(loc, key, "perturbed pattern needs a numeric scale, got %v", fields["scale"])
+		}
+		pat = NewPerturbedPattern(child, scale)
+	default:
+		return nil, p.errorf(loc, "type", "unknown pattern type %v", fields["type"])
+	}
+
+	for _, key := range sortedKeys(fields) {
+		found := false
+		for _, k := range keys {
+			found = found || key == k
+		}
+		if !found {
+			return nil, p.errorf(loc, key, "unknown pattern field %q", key)
+		}
+	}
+
+	if t, ok := fields["transform"]; ok {
+		tloc := p.at(loc, "transform")
+		m, err := p.parseTransform(tloc, t)
+		if err != nil {
+			return nil, err
+		}
+		pat.SetTransform(m)
+		if _, err := pat.Inverse(); err != nil {
+			return nil, p.errorf(tloc, "", "pattern transform can't be inverted")
+		}
+	}
+	return pat, nil
+}
+
+// parsePatternColor - a list of 3 numbers is a solid color, a map a nested pattern
+func (p *sceneParser) parsePatternColor(loc sceneLoc, v interface{}) (Pattern, error) {
+	if _, ok := v.(map[interface{}]interface{}); ok {
+		return p.parsePattern(loc, v)
+	}
+	c, ok := toTriple(v)
+	if !ok {
+		return nil, p.errorf(loc, "", "color must be a list of 3 numbers or a pattern, got %v", v)
+	}
+	return NewSolidPattern(Color{c[0], c[1], c[2]}), nil
+}
+
+// parseTransform - combine a list of transforms, the first listed is applied first
+func (p *sceneParser) parseTransform(loc sceneLoc, v interface{}) (Mat4, error) {
+	steps, ok := v.([]interface{})
+	if !ok {
+		return Mat4{}, p.errorf(loc, "", "transform must be a list")
+	}
+
+	m := NewIdentityMat4()
+	for j, step := range steps {
+		sloc := p.elem(loc, j)
+		if name, ok := step.(string); ok {
+			d, ok := p.defines[name]
+			if !ok {
+				return Mat4{}, p.errorf(sloc, "", "undefined transform %q", name)
+			}
+			if p.expanding[name] {
+				return Mat4{}, p.errorf(sloc, "", "define %q refers to itself", name)
+			}
+			p.expanding[name] = true
+			t, err := p.parseTransform(p.defineLocs[name], d)
+			delete(p.expanding, name)
+			if err != nil {
+				return Mat4{}, err
+			}
+			m = t.MulM(m)
+			continue
+		}
+
+		args, ok := step.([]interface{})
+		if !ok || len(args) == 0 {
+			return Mat4{}, p.errorf(sloc, "", "transform step must be a list like [translate, 1, 2, 3], got %v", step)
+		}
+		op, _ := args[0].(string)
+		nums := make([]float64, len(args)-1)
+		for j, a := range args[1:] {
+			if nums[j], ok = toFloat(a); !ok {
+				return Mat4{}, p.errorf(sloc, "", "%v: %v is not a number", op, a)
+			}
+		}
+
+		want := map[string]int{
+			"translate": 3, "scale": 3, "rotate-x": 1, "rotate-y": 1, "rotate-z": 1, "shear": 6,
+		}
+		n, ok := want[op]
+		if !ok {
+			return Mat4{}, p.errorf(sloc, "", "unknown transform %q", args[0])
+		}
+		if len(nums) != n {
+			return Mat4{}, p.errorf(sloc, "", "%v takes %v numbers, got %v", op, n, len(nums))
+		}
+		switch op {
+		case "translate":
+			m = m.Translate(nums[0], nums[1], nums[2])
+		case "scale":
+			m = m.Scale(nums[0], nums[1], nums[2])
+		case "rotate-x":
+			m = m.RotateX(nums[0])
+		case "rotate-y":
+			m = m.RotateY(nums[0])
+		case "rotate-z":
+			m = m.RotateZ(nums[0])
+		case "shear":
+			m = m.Shear(nums[0], nums[1], nums[2], nums[3], nums[4], nums[5])
+		}
+	}
+	return m, nil
+}
+
+// checkKeys - error at the first key of the item that isn't allowed
+func (p *sceneParser) checkKeys(loc sceneLoc, item map[string]interface{}, allowed ...string) error {
+	keys := make([]string, 0, len(item))
+	for k := range item {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		found := false
+		for _, a := range allowed {
+			found = found || k == a
+		}
+		if !found {
+			return p.errorf(loc, k, "unknown field %q", k)
+		}
+	}
+	return nil
+}
+
+func (p *sceneParser) floatField(loc sceneLoc, item map[string]interface{}, key string) (float64, error) {
+	v, ok := item[key]
+	if !ok {
+		return 0, p.errorf(loc, "", "missing %v", key)
+	}
+	f, ok := toFloat(v)
+	if !ok {
+		return 0, p.errorf(loc, key, "%v must be a number, got %v", key, v)
+	}
+	return f, nil
+}
+
+func (p *sceneParser) intField(loc sceneLoc, item map[string]interface{}, key string) (int, error) {
+	v, ok := item[key]
+	if !ok {
+		return 0, p.errorf(loc, "", "missing %v", key)
+	}
+	n, ok := v.(int)
+	if !ok {
+		return 0, p.errorf(loc, key, "%v must be a whole number, got %v", key, v)
+	}
+	return n, nil
+}
+
+func (p *sceneParser) tripleField(loc sceneLoc, item map[string]interface{}, key string) ([3]float64, error) {
+	v, ok := item[key]
+	if !ok {
+		return [3]float64{}, p.errorf(loc, "", "missing %v", key)
+	}
+	t, ok := toTriple(v)
+	if !ok {
+		return [3]float64{}, p.errorf(loc, key, "%v must be a list of 3 numbers, got %v", key, v)
+	}
+	return t, nil
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func toTriple(v interface{}) ([3]float64, bool) {
+	list, ok := v.([]interface{})
+	if !ok || len(list) != 3 {
+		return [3]float64{}, false
+	}
+	t := [3]float64{}
+	for i, e := range list {
+		if t[i], ok = toFloat(e); !ok {
+			return [3]float64{}, false
+		}
+	}
+	return t, true
+}
+
+// sortedKeys - string keys of a yaml map in order, so errors are deterministic
+func sortedKeys(m map[interface{}]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, fmt.Sprint(k))
+	}
+	sort.Strings(keys)
+	return keys
+}
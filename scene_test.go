@@ -0,0 +1,462 @@
+package main
+
+import (
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sceneCamera = `
+- add: camera
+  width: 100
+  height: 50
+  field-of-view: 0.785
+  from: [-6, 6, -10]
+  to: [6, 0, 6]
+  up: [-0.45, 1, 0]
+`
+
+/*
+	Scenario: Parsing a camera and a light
+	Given scene ← parse_scene(camera and light yaml)
+	Then scene.camera.hsize = 100
+	And scene.camera.vsize = 50
+	And scene.camera.field_of_view = 0.785
+	And scene.camera.transform = view_transform(point(-6, 6, -10), point(6, 0, 6), vector(-0.45, 1, 0))
+	And scene.world.lights = [point_light(point(50, 100, -50), color(1, 1, 1))]
+*/
+func TestParseSceneCameraAndLight(t *testing.T) {
+	scene, err := ParseScene(strings.NewReader(sceneCamera + `
+- add: light
+  at: [50, 100, -50]
+  intensity: [1, 1, 1]
+`))
+	require.Nil(t, err)
+	assert.Equal(t, 100, scene.Camera.HSize)
+	assert.Equal(t, 50, scene.Camera.VSize)
+	assert.Equal(t, 0.785, scene.Camera.FieldOfView)
+	view := ViewTransform(NewPoint(-6, 6, -10), NewPoint(6, 0, 6), NewVector(-0.45, 1, 0))
+	assert.True(t, scene.Camera.Transform().Equal(view))
+	assert.Equal(t, []PointLight{{NewPoint(50, 100, -50), Color{1, 1, 1}}}, scene.World.Lights)
+	assert.Empty(t, scene.World.Objects)
+}
+
+/*
+	Scenario: Parsing shapes with a material and transform
+	Given scene ← parse_scene(sphere and plane yaml)
+	Then scene.world.objects[0] is a sphere
+	And scene.world.objects[0].material.color = color(1, 0.2, 1)
+	And scene.world.objects[0].material.diffuse = 0.7
+	And scene.world.objects[0].material.ambient = 0.1
+	And scene.world.objects[0].material.reflective = 0.25
+	And scene.world.objects[0].material.transparency = 0.9
+	And scene.world.objects[0].material.refractive_index = 1.5
+	And scene.world.objects[0].transform = scaling(2, 2, 2) then translation(1, 2, 3)
+	And scene.world.objects[1] is a plane with the identity transform
+*/
+func TestParseSceneShapes(t *testing.T) {
+	scene, err := ParseScene(strings.NewReader(sceneCamera + `
+- add: sphere
+  material:
+    color: [1, 0.2, 1]
+    diffuse: 0.7
+    reflective: 0.25
+    transparency: 0.9
+    refractive-index: 1.5
+  transform:
+    - [scale, 2, 2, 2]
+    - [translate, 1, 2, 3]
+- add: plane
+`))
+	require.Nil(t, err)
+	require.Len(t, scene.World.Objects, 2)
+
+	s, ok := scene.World.Objects[0].(*Sphere)
+	require.True(t, ok)
+	assert.Equal(t, Color{1, 0.2, 1}, s.Material().Color)
+	assert.Equal(t, 0.7, s.Material().Diffuse)
+	assert.Equal(t, 0.1, s.Material().Ambient)
+	assert.Equal(t, 0.25, s.Material().Reflective)
+	assert.Equal(t, 0.9, s.Material().Transparency)
+	assert.Equal(t, 1.5, s.Material().RefractiveIndex)
+	assert.True(t, s.Transform().Equal(NewTranslation(1, 2, 3).MulM(NewScaling(2, 2, 2))))
+
+	p, ok := scene.World.Objects[1].(*Plane)
+	require.True(t, ok)
+	assert.True(t, p.Transform().Equal(NewIdentityMat4()))
+}
+
+/*
+	Scenario: Parsing every transform
+	Given scene ← parse_scene(cube with every transform)
+	Then scene.world.objects[0].transform =
+	    shearing(1, 0, 0, 0, 0, 0) * rotation_z(π/2) * rotation_y(π/3) * rotation_x(π/4)
+*/
+func TestParseSceneTransforms(t *testing.T) {
+	scene, err := ParseScene(strings.NewReader(sceneCamera + `
+- add: cube
+  transform:
+    - [rotate-x, 0.7853981634]
+    - [rotate-y, 1.0471975512]
+    - [rotate-z, 1.5707963268]
+    - [shear, 1, 0, 0, 0, 0, 0]
+`))
+	require.Nil(t, err)
+	want := NewShearing(1, 0, 0, 0, 0, 0).
+		MulM(NewRotationZ(math.Pi / 2)).
+		MulM(NewRotationY(math.Pi / 3)).
+		MulM(NewRotationX(math.Pi / 4))
+	assert.True(t, scene.World.Objects[0].Transform().Equal(want))
+}
+
+/*
+	Scenario: Parsing truncated cylinders and cones
+	Given scene ← parse_scene(cylinder and cone yaml)
+	Then scene.world.objects[0] is a cylinder from 0 to 2, closed
+	And scene.world.objects[1] is an open cone from -1 to infinity
+*/
+func TestParseSceneCylinderAndCone(t *testing.T) {
+	scene, err := ParseScene(strings.NewReader(sceneCamera + `
+- add: cylinder
+  min: 0
+  max: 2
+  closed: true
+- add: cone
+  min: -1.5
+`))
+	require.Nil(t, err)
+	cyl, ok := scene.World.Objects[0].(*Cylinder)
+	require.True(t, ok)
+	assert.Equal(t, 0.0, cyl.Minimum())
+	assert.Equal(t, 2.0, cyl.Maximum())
+	assert.True(t, cyl.Closed)
+
+	cone, ok := scene.World.Objects[1].(*Cone)
+	require.True(t, ok)
+	assert.Equal(t, -1.5, cone.Minimum())
+	assert.True(t, math.IsInf(cone.Maximum(), 1))
+	assert.False(t, cone.Closed)
+}
+
+/*
+	Scenario: Defines are used by name and can extend each other
+	Given scene ← parse_scene(yaml with defines)
+	Then scene.world.objects[0].material.color = color(1, 0, 0)
+	And scene.world.objects[0].material.ambient = 0.3
+	And scene.world.objects[0].material.diffuse = 0.5
+	And scene.world.objects[0].transform = translation(0, 1, 0) * scaling(0.5, 0.5, 0.5)
+*/
+func TestParseSceneDefines(t *testing.T) {
+	scene, err := ParseScene(strings.NewReader(sceneCamera + `
+- define: base-material
+  value:
+    ambient: 0.3
+    diffuse: 0.6
+- define: red-material
+  extend: base-material
+  value:
+    color: [1, 0, 0]
+    diffuse: 0.5
+- define: standard-transform
+  value:
+    - [scale, 0.5, 0.5, 0.5]
+- add: sphere
+  material: red-material
+  transform:
+    - standard-transform
+    - [translate, 0, 1, 0]
+`))
+	require.Nil(t, err)
+	m := scene.World.Objects[0].Material()
+	assert.Equal(t, Color{1, 0, 0}, m.Color)
+	assert.Equal(t, 0.3, m.Ambient)
+	assert.Equal(t, 0.5, m.Diffuse)
+	assert.Equal(t, 0.9, m.Specular)
+	want := NewTranslation(0, 1, 0).MulM(NewScaling(0.5, 0.5, 0.5))
+	assert.True(t, scene.World.Objects[0].Transform().Equal(want))
+}
+
+/*
+	Scenario Outline: Scene errors report where they are
+	When parse_scene(<yaml>) fails
+	Then the error is <error>
+
+	Examples:
+		| yaml                                 | error                                       |
+		| sphere with transform [wobble, 1]    | line 12: unknown transform "wobble"         |
+		| sphere with material colour          | line 12: unknown material field "colour"    |
+		| sphere with material: missing        | line 11: undefined material "missing"       |
+		| add: torus                           | line 10: unknown item "torus"               |
+		| sphere with radius                   | line 11: unknown field "radius"             |
+		| light at [1, 2]                      | line 11: at must be a list of 3 numbers ... |
+		| translate with 2 numbers             | line 12: translate takes 3 numbers, got 2   |
+		| define a with value [a]              | line 12: define "a" refers to itself        |
+		| defines a and b referring to another | line 15: define "a" refers to itself        |
+		| sphere scaled by 0 in x              | line 11: transform can't be inverted        |
+		| pattern scaled by 0 in x             | line 17: pattern transform can't be ...   |
+		| camera from and to the same point    | line 6: camera looks from and to the same   |
+		| camera up along the view direction   | line 7: camera up [0 1 0] is zero or ...    |
+		| camera field-of-view 0               | line 4: field-of-view must be between ...   |
+		| camera field-of-view 3.1416          | line 4: field-of-view must be between ...   |
+		| camera field-of-view -1              | line 4: field-of-view must be between ...   |
+		| no camera                            | scene has no camera                         |
+		| bad yaml                             | yaml: line 1: did not find expected key     |
+*/
+func TestParseSceneErrors(t *testing.T) {
+	examples := []struct {
+		yaml string
+		err  string
+	}{
+		{sceneCamera + "\n- add: sphere\n  transform:\n    - [wobble, 1]\n", `line 12: unknown transform "wobble"`},
+		{sceneCamera + "\n- add: sphere\n  material:\n    colour: [1, 0, 0]\n", `line 12: unknown material field "colour"`},
+		{sceneCamera + "\n- add: sphere\n  material: missing\n", `line 11: undefined material "missing"`},
+		{sceneCamera + "\n- add: torus\n", `line 10: unknown item "torus"`},
+		{sceneCamera + "\n- add: sphere\n  radius: 2\n", `line 11: unknown field "radius"`},
+		{sceneCamera + "\n- add: light\n  at: [1, 2]\n  intensity: [1, 1, 1]\n", `line 11: at must be a list of 3 numbers, got [1 2]`},
+		{sceneCamera + "\n- add: sphere\n  transform:\n    - [translate, 1, 2]\n", `line 12: translate takes 3 numbers, got 2`},
+		{sceneCamera + "\n- define: a\n  value:\n    - a\n- add: sphere\n  transform:\n    - a\n", `line 12: define "a" refers to itself`},
+		{sceneCamera + "\n- define: a\n  value:\n    - b\n- define: b\n  value:\n    - a\n- add: sphere\n  transform:\n    - a\n", `line 15: define "a" refers to itself`},
+		{sceneCamera + "\n- add: sphere\n  transform:\n    - [scale, 0, 1, 1]\n", `line 11: transform can't be inverted`},
+		{sceneCamera + "\n- add: plane\n  material:\n    pattern:\n      type: stripes\n      colors:\n        - [1, 1, 1]\n        - [0, 0, 0]\n      transform:\n        - [scale, 0, 1, 1]\n", `line 17: pattern transform can't be inverted`},
+		{"- add: camera\n  width: 10\n  height: 10\n  field-of-view: 1\n  from: [0, 1, 0]\n  to: [0, 1, 0]\n  up: [0, 1, 0]\n", `line 6: camera looks from and to the same point`},
+		{"- add: camera\n  width: 10\n  height: 10\n  field-of-view: 1\n  from: [0, 0, 0]\n  to: [0, 1, 0]\n  up: [0, 1, 0]\n", `line 7: camera up [0 1 0] is zero or parallel to the view direction`},
+		{"- add: camera\n  width: 10\n  height: 10\n  field-of-view: 0\n  from: [0, 0, -5]\n  to: [0, 0, 0]\n  up: [0, 1, 0]\n", `line 4: field-of-view must be between 0 and π radians, got 0`},
+		{"- add: camera\n  width: 10\n  height: 10\n  field-of-view: 3.1416\n  from: [0, 0, -5]\n  to: [0, 0, 0]\n  up: [0, 1, 0]\n", `line 4: field-of-view must be between 0 and π radians, got 3.1416`},
+		{"- add: camera\n  width: 10\n  height: 10\n  field-of-view: -1\n  from: [0, 0, -5]\n  to: [0, 0, 0]\n  up: [0, 1, 0]\n", `line 4: field-of-view must be between 0 and π radians, got -1`},
+		{"- add: sphere\n", `scene has no camera`},
+		{"- add: sphere\n  - material\n", `yaml: line 1: did not find expected key`},
+	}
+	for _, e := range examples {
+		_, err := ParseScene(strings.NewReader(e.yaml))
+		require.NotNil(t, err, e.yaml)
+		assert.Contains(t, err.Error(), e.err)
+	}
+}
+
+/*
+	Scenario Outline: Errors are placed at the key they are about, not one of the same name
+	Given a sphere whose pattern has a transform on line 15
+	And the sphere's own <transform> on line 16
+	When parse_scene fails
+	Then the error is <error>
+
+	Examples:
+		| transform              | error                                      |
+		| [[scale, 0, 0, 0]]     | line 16: transform can't be inverted       |
+		| [[squash, 1]]          | line 16: unknown transform "squash"        |
+		| block list of [squash] | line 17: unknown transform "squash"        |
+*/
+func TestParseSceneErrorRepeatedKey(t *testing.T) {
+	sphere := sceneCamera + `
+- add: sphere
+  material:
+    pattern:
+      type: stripes
+      colors: [[1, 1, 1], [0, 0, 0]]
+      transform: [[scale, 2, 2, 2]]
+  transform: `
+	examples := []struct {
+		transform string
+		err       string
+	}{
+		{"[[scale, 0, 0, 0]]\n", `line 16: transform can't be inverted`},
+		{"[[squash, 1]]\n", `line 16: unknown transform "squash"`},
+		{"\n    - [squash, 1]\n", `line 17: unknown transform "squash"`},
+	}
+	for _, e := range examples {
+		_, err := ParseScene(strings.NewReader(sphere + e.transform))
+		require.NotNil(t, err)
+		assert.Equal(t, e.err, err.Error())
+	}
+}
+
+/*
+	Scenario: Errors in an indented document still carry their line
+	Given a scene whose top level list is indented by 2 spaces
+	And the camera's width is "x" on line 3
+	When parse_scene fails
+	Then the error is "line 3: width must be a whole number, got x"
+*/
+func TestParseSceneErrorIndented(t *testing.T) {
+	_, err := ParseScene(strings.NewReader(`
+  - add: camera
+    width: x
+    height: 50
+    field-of-view: 0.785
+    from: [0, 0, -5]
+    to: [0, 0, 0]
+    up: [0, 1, 0]
+`))
+	require.NotNil(t, err)
+	assert.Equal(t, "line 3: width must be a whole number, got x", err.Error())
+}
+
+/*
+	Scenario: Errors in a define are placed in the define
+	Given a material define with shininess "high" on line 12
+	And a sphere using it
+	When parse_scene fails
+	Then the error is "line 12: shininess must be a number, got high"
+*/
+func TestParseSceneErrorInDefine(t *testing.T) {
+	_, err := ParseScene(strings.NewReader(sceneCamera + `
+- define: shiny
+  value:
+    shininess: high
+- add: sphere
+  material: shiny
+`))
+	require.NotNil(t, err)
+	assert.Equal(t, "line 12: shininess must be a number, got high", err.Error())
+}
+
+/*
+	Scenario: Loading a scene file names the file in errors
+	Given "test.yaml" holds a scene with an unknown shape
+	When load_scene("test.yaml") fails
+	Then the error starts with "test.yaml: line 10:"
+*/
+func TestLoadSceneError(t *testing.T) {
+	f, err := os.Create("test.yaml")
+	require.Nil(t, err)
+	f.WriteString(sceneCamera + "\n- add: torus\n")
+	f.Close()
+	defer os.Remove("test.yaml")
+
+	_, err = LoadScene("test.yaml")
+	require.NotNil(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "test.yaml: line 10:"), err.Error())
+}
+
+/*
+	Scenario: The example scene matches the built in one
+	When scene ← load_scene("scenes/spheres.yaml")
+	Then scene.world has 3 objects and 1 light
+	And the second sphere's material is the default with color(0.8, 0.2, 0.3)
+*/
+func TestLoadSceneExample(t *testing.T) {
+	scene, err := LoadScene("scenes/spheres.yaml")
+	require.Nil(t, err)
+	assert.Len(t, scene.World.Objects, 3)
+	assert.Len(t, scene.World.Lights, 1)
+	assert.Equal(t, DefaultMaxDepth, scene.World.MaxDepth)
+	want := NewMaterial()
+	want.Color = Color{0.8, 0.2, 0.3}
+	assert.Equal(t, want, *scene.World.Objects[2].Material())
+	assert.True(t, scene.World.Objects[2].Transform().Equal(NewScaling(0.6, 0.6, 1).Translate(-0.5, -0.5, 0)))
+}
+
+/*
+	Scenario: Parsing a material pattern
+	Given scene ← parse_scene(plane with a checkers pattern)
+	Then scene.world.objects[0].material.pattern is a checkers pattern of white and black
+	And its transform = scaling(0.25, 0.25, 0.25)
+*/
+func TestParseScenePattern(t *testing.T) {
+	scene, err := ParseScene(strings.NewReader(sceneCamera + `
+- add: plane
+  material:
+    pattern:
+      type: checkers
+      colors:
+        - [1, 1, 1]
+        - [0, 0, 0]
+      transform:
+        - [scale, 0.25, 0.25, 0.25]
+`))
+	require.Nil(t, err)
+	p, ok := scene.World.Objects[0].Material().Pattern.(*CheckerPattern)
+	require.True(t, ok)
+	assert.Equal(t, NewSolidPattern(White), p.A)
+	assert.Equal(t, NewSolidPattern(Black), p.B)
+	assert.True(t, p.Transform().Equal(NewScaling(0.25, 0.25, 0.25)))
+
+	_, err = ParseScene(strings.NewReader(sceneCamera + `
+- add: plane
+  material:
+    pattern:
+      type: zigzag
+      colors: [[1, 1, 1], [0, 0, 0]]
+`))
+	require.NotNil(t, err)
+	assert.Equal(t, "line 13: unknown pattern type zigzag", err.Error())
+}
+
+/*
+	Scenario: Parsing nested, blended and perturbed patterns
+	Given scene ← parse_scene(plane with a perturbed blend of stripes and checkers of a solid and rings)
+	Then scene.world.objects[0].material.pattern is a perturbed pattern with scale 0.3
+	And its pattern is a blend of stripes and checkers
+	And the checkers' second color is a rings pattern
+*/
+func TestParseSceneNestedPatterns(t *testing.T) {
+	scene, err := ParseScene(strings.NewReader(sceneCamera + `
+- add: plane
+  material:
+    pattern:
+      type: perturbed
+      scale: 0.3
+      pattern:
+        type: blend
+        colors:
+          - type: stripes
+            colors: [[1, 1, 1], [0, 0, 0]]
+            transform:
+              - [rotate-y, 0.5]
+          - type: checkers
+            colors:
+              - [1, 0, 0]
+              - type: rings
+                colors: [[0, 1, 0], [0, 0, 1]]
+`))
+	require.Nil(t, err)
+	perturbed, ok := scene.World.Objects[0].Material().Pattern.(*PerturbedPattern)
+	require.True(t, ok)
+	assert.Equal(t, 0.3, perturbed.Scale)
+	blend, ok := perturbed.Pattern.(*BlendPattern)
+	require.True(t, ok)
+	stripes, ok := blend.A.(*StripePattern)
+	require.True(t, ok)
+	assert.True(t, stripes.Transform().Equal(NewRotationY(0.5)))
+	checkers, ok := blend.B.(*CheckerPattern)
+	require.True(t, ok)
+	assert.Equal(t, NewSolidPattern(Color{1, 0, 0}), checkers.A)
+	_, ok = checkers.B.(*RingPattern)
+	assert.True(t, ok)
+}
+
+/*
+	Scenario: A perturbed pattern needs a scale
+	When parse_scene(plane with a perturbed pattern and no scale) fails
+	Then the error is "line 13: perturbed pattern needs a numeric scale, got <nil>"
+*/
+func TestParseScenePerturbedNeedsScale(t *testing.T) {
+	_, err := ParseScene(strings.NewReader(sceneCamera + `
+- add: plane
+  material:
+    pattern:
+      type: perturbed
+      pattern:
+        type: stripes
+        colors: [[1, 1, 1], [0, 0, 0]]
+`))
+	require.NotNil(t, err)
+	assert.Equal(t, "line 13: perturbed pattern needs a numeric scale, got <nil>", err.Error())
+}
+
+/*
+	Scenario: The marble example scene loads
+	When scene ← load_scene("scenes/marble.yaml")
+	Then scene.world.objects[0].material.pattern is a perturbed pattern
+*/
+func TestLoadSceneMarble(t *testing.T) {
+	scene, err := LoadScene("scenes/marble.yaml")
+	require.Nil(t, err)
+	require.Len(t, scene.World.Objects, 3)
+	_, ok := scene.World.Objects[0].Material().Pattern.(*PerturbedPattern)
+	assert.True(t, ok)
+}
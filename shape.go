@@ -0,0 +1,141 @@
+package main
+
+// Shape - anything a ray can be intersected with and shaded.
+// Implementations only deal with object space, Intersect and NormalAt
+// handle the conversion to and from world space
+type Shape interface {
+	Transform() Mat4
+	SetTransform(m Mat4)
+	// Inverse - inverse of the transform, worked out when the transform is set
+	Inverse() (Mat4, error)
+	// NormalMatrix - transpose of the inverse, takes object space normals to the parent's space
+	NormalMatrix() (Mat4, error)
+	Material() *Material
+	SetMaterial(m Material)
+	// Parent - group or csg this shape belongs to, nil at the top of the world
+	Parent() Shape
+	setParent(p Shape)
+	// boundsChanged - a child's bounds moved, containers refresh their own
+	boundsChanged()
+
+	// Bounds - object space box holding the whole shape
+	Bounds() BoundingBox
+
+	// LocalIntersect - intersect a ray already converted to object space
+	LocalIntersect(r Ray) Intersections
+	// LocalNormalAt - normal at a point already converted to object space,
+	// hit is the intersection the point came from
+	LocalNormalAt(p Tuple, hit Intersection) Tuple
+}
+
+// shape - transform and material shared by every Shape, meant to be embedded
+type shape struct {
+	transform Mat4
+	material  Material
+	parent    Shape
+
+	// inverse and normalMatrix are cached from the transform, every ray and
+	// normal needs them and inverting is far more expensive than using them.
+	// inverseErr is kept for transforms that can't be inverted
+	inverse      Mat4
+	normalMatrix Mat4
+	inverseErr   error
+}
+
+func newShape() shape {
+	return shape{
+		transform:    NewIdentityMat4(),
+		material:     NewMaterial(),
+		inverse:      NewIdentityMat4(),
+		normalMatrix: NewIdentityMat4(),
+	}
+}
+
+func (s *shape) Transform() Mat4 {
+	return s.transform
+}
+
+// SetTransform - set the transform and the matrices cached from it
+func (s *shape) SetTransform(m Mat4) {
+	s.transform = m
+	s.inverse, s.inverseErr = m.Inverse()
+	s.normalMatrix = s.inverse.Transpose()
+	if s.parent != nil {
+		s.parent.boundsChanged()
+	}
+}
+
+func (s *shape) Inverse() (Mat4, error) {
+	return s.inverse, s.inverseErr
+}
+
+func (s *shape) NormalMatrix() (Mat4, error) {
+	return s.normalMatrix, s.inverseErr
+}
+
+func (s *shape) Material() *Material {
+	return &s.material
+}
+
+func (s *shape) SetMaterial(m Material) {
+	s.material = m
+}
+
+func (s *shape) Parent() Shape {
+	return s.parent
+}
+
+func (s *shape) setParent(p Shape) {
+	s.parent = p
+}
+
+// boundsChanged - primitives have nothing cached, groups and csgs override this
+func (s *shape) boundsChanged() {}
+
+// Intersect - intersect a world space ray with a shape
+func Intersect(s Shape, r Ray) (Intersections, error) {
+	inv, err := s.Inverse()
+	if err != nil {
+		return nil, err
+	}
+	return s.LocalIntersect(r.Transform(inv)), nil
+}
+
+// NormalAt - world space normal of a shape at a world space point,
+// hit is the intersection that produced the point
+func NormalAt(s Shape, p Tuple, hit Intersection) Tuple {
+	objectPoint := WorldToObject(s, p)
+	objectNormal := s.LocalNormalAt(objectPoint, hit)
+	return NormalToWorld(s, objectNormal)
+}
+
+// WorldToObject - convert a world space point into the shape's object space,
+// passing through the object space of every group or csg above it.
+// Panics if a transform can't be inverted
+func WorldToObject(s Shape, p Tuple) Tuple {
+	if parent := s.Parent(); parent != nil {
+		p = WorldToObject(parent, p)
+	}
+	inv, err := s.Inverse()
+	if err != nil {
+		panic(err)
+	}
+	return inv.MulT(p)
+}
+
+// NormalToWorld - convert an object space normal into world space,
+// passing through the object space of every group or csg above the shape.
+// Panics if a transform can't be inverted
+func NormalToWorld(s Shape, n Tuple) Tuple {
+	nm, err := s.NormalMatrix()
+	if err != nil {
+		panic(err)
+	}
+	n = nm.MulT(n)
+	n.W = 0
+	n = n.Norm()
+	if parent := s.Parent(); parent != nil {
+		n = NormalToWorld(parent, n)
+	}
+	return n
+}
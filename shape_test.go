@@ -0,0 +1,269 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testShape - records the object space ray it was intersected with
+type testShape struct {
+	shape
+	savedRay Ray
+}
+
+func newTestShape() *testShape {
+	return &testShape{shape: newShape()}
+}
+
+func (s *testShape) LocalIntersect(r Ray) Intersections {
+	s.savedRay = r
+	return Intersections{}
+}
+
+func (s *testShape) LocalNormalAt(p Tuple, _ Intersection) Tuple {
+	return NewVector(p.X, p.Y, p.Z)
+}
+
+func (s *testShape) Bounds() BoundingBox {
+	return NewBoundingBoxFrom(NewPoint(-1, -1, -1), NewPoint(1, 1, 1))
+}
+
+/*
+	Scenario: The default transformation
+	Given s ← test_shape()
+	Then s.transform = identity_matrix
+*/
+func TestShapeDefaultTransformation(t *testing.T) {
+	s := newTestShape()
+	assert.Equal(t, NewIdentityMat4(), s.Transform())
+}
+
+/*
+	Scenario: Assigning a transformation
+	Given s ← test_shape()
+	When set_transform(s, translation(2, 3, 4))
+	Then s.transform = translation(2, 3, 4)
+*/
+func TestShapeAssignTransformation(t *testing.T) {
+	s := newTestShape()
+	s.SetTransform(NewTranslation(2, 3, 4))
+	assert.Equal(t, NewTranslation(2, 3, 4), s.Transform())
+}
+
+/*
+	Scenario: The default material
+	Given s ← test_shape()
+	When m ← s.material
+	Then m = material()
+*/
+func TestShapeDefaultMaterial(t *testing.T) {
+	s := newTestShape()
+	assert.Equal(t, NewMaterial(), *s.Material())
+}
+
+/*
+	Scenario: Assigning a material
+	Given s ← test_shape()
+	And m ← material()
+	And m.ambient ← 1
+	When s.material ← m
+	Then s.material = m
+*/
+func TestShapeAssignMaterial(t *testing.T) {
+	s := newTestShape()
+	m := NewMaterial()
+	m.Ambient = 1
+	s.SetMaterial(m)
+	assert.Equal(t, m, *s.Material())
+}
+
+/*
+	Scenario: Assigning a transformation caches its inverse
+	Given s ← test_shape()
+	When set_transform(s, translation(2, 3, 4))
+	Then s.inverse = inverse(translation(2, 3, 4))
+	And s.normal_matrix = transpose(inverse(translation(2, 3, 4)))
+*/
+func TestShapeCachesInverse(t *testing.T) {
+	s := newTestShape()
+	s.SetTransform(NewTranslation(2, 3, 4))
+	inv, err := s.Inverse()
+	require.Nil(t, err)
+	assert.Equal(t, NewTranslation(2, 3, 4).MustInverse(), inv)
+	nm, err := s.NormalMatrix()
+	require.Nil(t, err)
+	assert.Equal(t, NewTranslation(2, 3, 4).MustInverse().Transpose(), nm)
+}
+
+/*
+	Scenario: Changing a transformation refreshes the cached inverse
+	Given s ← test_shape()
+	And set_transform(s, translation(2, 3, 4))
+	When set_transform(s, scaling(2, 2, 2))
+	Then s.inverse = scaling(0.5, 0.5, 0.5)
+	And s.normal_matrix = scaling(0.5, 0.5, 0.5)
+*/
+func TestShapeRefreshesCachedInverse(t *testing.T) {
+	s := newTestShape()
+	s.SetTransform(NewTranslation(2, 3, 4))
+	s.SetTransform(NewScaling(2, 2, 2))
+	inv, err := s.Inverse()
+	require.Nil(t, err)
+	assert.Equal(t, NewScaling(0.5, 0.5, 0.5), inv)
+	nm, err := s.NormalMatrix()
+	require.Nil(t, err)
+	assert.Equal(t, NewScaling(0.5, 0.5, 0.5), nm)
+}
+
+/*
+	Scenario: A non-invertible transformation has no cached inverse
+	Given s ← test_shape()
+	When set_transform(s, scaling(0, 0, 0))
+	Then inverse(s) is an error
+	And normal_matrix(s) is an error
+*/
+func TestShapeNonInvertibleCachedInverse(t *testing.T) {
+	s := newTestShape()
+	s.SetTransform(NewScaling(0, 0, 0))
+	_, err := s.Inverse()
+	assert.NotNil(t, err)
+	_, err = s.NormalMatrix()
+	assert.NotNil(t, err)
+
+	// setting an invertible transform again clears the error
+	s.SetTransform(NewScaling(2, 2, 2))
+	_, err = s.Inverse()
+	assert.Nil(t, err)
+}
+
+/*
+	Scenario: Intersecting a scaled shape with a ray
+	Given r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And s ← test_shape()
+	When set_transform(s, scaling(2, 2, 2))
+	And xs ← intersect(s, r)
+	Then s.saved_ray.origin = point(0, 0, -2.5)
+	And s.saved_ray.direction = vector(0, 0, 0.5)
+*/
+func TestIntersectScaledShape(t *testing.T) {
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	s := newTestShape()
+	s.SetTransform(NewScaling(2, 2, 2))
+	_, err := Intersect(s, r)
+	require.Nil(t, err)
+	assert.True(t, s.savedRay.Origin.Equal(NewPoint(0, 0, -2.5)))
+	assert.True(t, s.savedRay.Direction.Equal(NewVector(0, 0, 0.5)))
+}
+
+/*
+	Scenario: Intersecting a translated shape with a ray
+	Given r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And s ← test_shape()
+	When set_transform(s, translation(5, 0, 0))
+	And xs ← intersect(s, r)
+	Then s.saved_ray.origin = point(-5, 0, -5)
+	And s.saved_ray.direction = vector(0, 0, 1)
+*/
+func TestIntersectTranslatedShape(t *testing.T) {
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	s := newTestShape()
+	s.SetTransform(NewTranslation(5, 0, 0))
+	_, err := Intersect(s, r)
+	require.Nil(t, err)
+	assert.True(t, s.savedRay.Origin.Equal(NewPoint(-5, 0, -5)))
+	assert.True(t, s.savedRay.Direction.Equal(NewVector(0, 0, 1)))
+}
+
+/*
+	Scenario: Intersecting a shape with a non-invertible transform
+	Given r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And s ← test_shape()
+	When set_transform(s, scaling(0, 0, 0))
+	Then intersect(s, r) is an error
+*/
+func TestIntersectNonInvertibleShape(t *testing.T) {
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	s := newTestShape()
+	s.SetTransform(NewScaling(0, 0, 0))
+	_, err := Intersect(s, r)
+	assert.NotNil(t, err)
+}
+
+/*
+	Scenario: Computing the normal on a translated shape
+	Given s ← test_shape()
+	When set_transform(s, translation(0, 1, 0))
+	And n ← normal_at(s, point(0, 1.70711, -0.70711))
+	Then n = vector(0, 0.70711, -0.70711)
+*/
+func TestNormalTranslatedShape(t *testing.T) {
+	s := newTestShape()
+	s.SetTransform(NewTranslation(0, 1, 0))
+	n := NormalAt(s, NewPoint(0, 1.70711, -0.70711), Intersection{})
+	assert.True(t, n.Equal(NewVector(0, 0.70711, -0.70711)))
+}
+
+/*
+	Scenario: Computing the normal on a transformed shape
+	Given s ← test_shape()
+	And m ← scaling(1, 0.5, 1) * rotation_z(π/5)
+	When set_transform(s, m)
+	And n ← normal_at(s, point(0, √2/2, -√2/2))
+	Then n = vector(0, 0.97014, -0.24254)
+*/
+func TestNormalTransformedShape(t *testing.T) {
+	s := newTestShape()
+	s.SetTransform(NewRotationZ(math.Pi / 5).Scale(1, 0.5, 1))
+	n := NormalAt(s, NewPoint(0, math.Sqrt2/2, -math.Sqrt2/2), Intersection{})
+	assert.True(t, n.Equal(NewVector(0, 0.97014, -0.24254)))
+}
+
+func benchmarkSphere() *Sphere {
+	return NewSphere().WithTransform(NewScaling(2, 2, 2).RotateY(math.Pi / 5).Translate(1, 2, 3))
+}
+
+// BenchmarkIntersectUncached - inverting the transform as a general Matrix on every ray, as Intersect used to
+func BenchmarkIntersectUncached(b *testing.B) {
+	s := benchmarkSphere()
+	r := Ray{NewPoint(1, 2, -5), NewVector(0, 0, 1)}
+	for i := 0; i < b.N; i++ {
+		inv, err := NewMat4FromMatrix(s.Transform().Matrix().MustInverse())
+		if err != nil {
+			b.Fatal(err)
+		}
+		s.LocalIntersect(r.Transform(inv))
+	}
+}
+
+func BenchmarkIntersect(b *testing.B) {
+	s := benchmarkSphere()
+	r := Ray{NewPoint(1, 2, -5), NewVector(0, 0, 1)}
+	for i := 0; i < b.N; i++ {
+		if _, err := Intersect(s, r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkNormalAtUncached - inverting the transform as a general Matrix twice per normal, as NormalAt used to
+func BenchmarkNormalAtUncached(b *testing.B) {
+	s := benchmarkSphere()
+	p := NewPoint(1, 2, 1)
+	for i := 0; i < b.N; i++ {
+		op := s.Transform().Matrix().MustInverse().MustMulT(p)
+		n := s.Transform().Matrix().MustInverse().MustTranspose().MustMulT(s.LocalNormalAt(op, Intersection{}))
+		n.W = 0
+		n.Norm()
+	}
+}
+
+func BenchmarkNormalAt(b *testing.B) {
+	s := benchmarkSphere()
+	p := NewPoint(1, 2, 1)
+	for i := 0; i < b.N; i++ {
+		NormalAt(s, p, Intersection{})
+	}
+}
@@ -4,29 +4,34 @@ import (
 	"math"
 )
 
+// Sphere - unit sphere centered on the origin
 type Sphere struct {
-	Transform Matrix
-	Material  Material
+	shape
 }
 
-func NewSphere() Sphere {
-	return Sphere{NewIdentityMatrix(4), NewMaterial()}
+func NewSphere() *Sphere {
+	return &Sphere{newShape()}
 }
 
-func (s Sphere) WithTransform(t Matrix) Sphere {
-	return Sphere{t, NewMaterial()}
+// NewGlassSphere - unit sphere of clear glass
+func NewGlassSphere() *Sphere {
+	s := NewSphere()
+	s.Material().Transparency = 1
+	s.Material().RefractiveIndex = RefractiveGlass
+	return s
 }
 
-func (s Sphere) WithMaterial(m Material) Sphere {
-	return Sphere{s.Transform, m}
+func (s *Sphere) WithTransform(t Mat4) *Sphere {
+	s.SetTransform(t)
+	return s
 }
 
-func (s Sphere) Intersect(r Ray) (Intersections, error) {
-	tm, err := s.Transform.Inverse()
-	if err != nil {
-		return nil, err
-	}
-	r = r.Transform(tm)
+func (s *Sphere) WithMaterial(m Material) *Sphere {
+	s.SetMaterial(m)
+	return s
+}
+
+func (s *Sphere) LocalIntersect(r Ray) Intersections {
 	sphereToRay := r.Origin.Sub(NewPoint(0, 0, 0))
 	a := r.Direction.Dot(r.Direction)
 	b := r.Direction.Dot(sphereToRay) * 2.0
@@ -34,19 +39,19 @@ func (s Sphere) Intersect(r Ray) (Intersections, error) {
 	discriminant := (b * b) - (4 * a * c)
 
 	if discriminant < 0 {
-		return Intersections{}, nil
+		return Intersections{}
 	}
 
 	t1 := (-b - math.Sqrt(discriminant)) / (2 * a)
 	t2 := (-b + math.Sqrt(discriminant)) / (2 * a)
 
-	return Intersections{Intersection{t1, s}, Intersection{t2, s}}, nil
+	return Intersections{NewIntersection(t1, s), NewIntersection(t2, s)}
+}
+
+func (s *Sphere) LocalNormalAt(p Tuple, _ Intersection) Tuple {
+	return p.Sub(NewPoint(0, 0, 0))
 }
 
-func (s Sphere) NormalAt(p Tuple) Tuple {
-	objectPoint := s.Transform.MustInverse().MustMulT(p)
-	objectNormal := objectPoint.Sub(NewPoint(0, 0, 0))
-	worldNormal := s.Transform.MustInverse().MustTranspose().MustMulT(objectNormal)
-	worldNormal.W = 0
-	return worldNormal.Norm()
+func (s *Sphere) Bounds() BoundingBox {
+	return NewBoundingBoxFrom(NewPoint(-1, -1, -1), NewPoint(1, 1, 1))
 }
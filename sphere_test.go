@@ -20,7 +20,7 @@ import (
 func TestRayIntersectSphere2Points(t *testing.T) {
 	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
 	s := NewSphere()
-	xs, err := s.Intersect(r)
+	xs, err := Intersect(s, r)
 	require.Nil(t, err)
 	assert.Equal(t, 2, len(xs))
 	assert.Equal(t, 4.0, xs[0].T)
@@ -39,7 +39,7 @@ func TestRayIntersectSphere2Points(t *testing.T) {
 func TestRayIntersectSphereTangent(t *testing.T) {
 	r := Ray{NewPoint(0, 1, -5), NewVector(0, 0, 1)}
 	s := NewSphere()
-	xs, err := s.Intersect(r)
+	xs, err := Intersect(s, r)
 	require.Nil(t, err)
 	assert.Equal(t, 2, len(xs))
 	assert.Equal(t, 5.0, xs[0].T)
@@ -56,7 +56,7 @@ func TestRayIntersectSphereTangent(t *testing.T) {
 func TestRayMissesShpere(t *testing.T) {
 	r := Ray{NewPoint(0, 2, -5), NewVector(0, 0, 1)}
 	s := NewSphere()
-	xs, err := s.Intersect(r)
+	xs, err := Intersect(s, r)
 	require.Nil(t, err)
 	assert.Equal(t, 0, len(xs))
 }
@@ -73,7 +73,7 @@ func TestRayMissesShpere(t *testing.T) {
 func TestRayOriginatesInsideSphere(t *testing.T) {
 	r := Ray{NewPoint(0, 0, 0), NewVector(0, 0, 1)}
 	s := NewSphere()
-	xs, err := s.Intersect(r)
+	xs, err := Intersect(s, r)
 	require.Nil(t, err)
 	assert.Equal(t, 2, len(xs))
 	assert.Equal(t, -1.0, xs[0].T)
@@ -92,7 +92,7 @@ func TestRayOriginatesInsideSphere(t *testing.T) {
 func TestSphereBehindRay(t *testing.T) {
 	r := Ray{NewPoint(0, 0, 5), NewVector(0, 0, 1)}
 	s := NewSphere()
-	xs, err := s.Intersect(r)
+	xs, err := Intersect(s, r)
 	require.Nil(t, err)
 	assert.Equal(t, 2, len(xs))
 	assert.Equal(t, -6.0, xs[0].T)
@@ -111,7 +111,7 @@ func TestSphereBehindRay(t *testing.T) {
 func TestIntersectSetsIntersectionObject(t *testing.T) {
 	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
 	s := NewSphere()
-	xs, err := s.Intersect(r)
+	xs, err := Intersect(s, r)
 	require.Nil(t, err)
 	assert.Equal(t, 2, len(xs))
 	assert.Equal(t, xs[0].Object, s)
@@ -125,7 +125,7 @@ func TestIntersectSetsIntersectionObject(t *testing.T) {
 */
 func TestSphereDefaultTransformation(t *testing.T) {
 	s := NewSphere()
-	assert.Equal(t, NewIdentityMatrix(4), s.Transform)
+	assert.Equal(t, NewIdentityMat4(), s.Transform())
 }
 
 /*
@@ -138,8 +138,8 @@ func TestSphereDefaultTransformation(t *testing.T) {
 func TestSetSphereTransformation(t *testing.T) {
 	s := NewSphere()
 	transform := NewTranslation(2, 3, 4)
-	s.Transform = transform
-	assert.Equal(t, transform, s.Transform)
+	s.SetTransform(transform)
+	assert.Equal(t, transform, s.Transform())
 }
 
 /*
@@ -151,7 +151,7 @@ func TestSetSphereTransformation(t *testing.T) {
 func TestSphereWithTransformation(t *testing.T) {
 	transform := NewTranslation(2, 3, 4)
 	s := NewSphere().WithTransform(transform)
-	assert.Equal(t, transform, s.Transform)
+	assert.Equal(t, transform, s.Transform())
 }
 
 /*
@@ -167,7 +167,7 @@ func TestSphereWithTransformation(t *testing.T) {
 func TestIntersectScaledSphere(t *testing.T) {
 	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
 	s := NewSphere().WithTransform(NewScaling(2, 2, 2))
-	xs, err := s.Intersect(r)
+	xs, err := Intersect(s, r)
 	require.Nil(t, err)
 	assert.Equal(t, 2, len(xs))
 	assert.Equal(t, 3.0, xs[0].T)
@@ -185,7 +185,7 @@ func TestIntersectScaledSphere(t *testing.T) {
 func TestIntersectTranslatedSphere(t *testing.T) {
 	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
 	s := NewSphere().WithTransform(NewTranslation(5, 0, 0))
-	xs, err := s.Intersect(r)
+	xs, err := Intersect(s, r)
 	require.Nil(t, err)
 	assert.Equal(t, 0, len(xs))
 }
@@ -198,7 +198,7 @@ func TestIntersectTranslatedSphere(t *testing.T) {
 */
 func TestNormalPointXAxis(t *testing.T) {
 	s := NewSphere()
-	n := s.NormalAt(NewPoint(1, 0, 0))
+	n := NormalAt(s, NewPoint(1, 0, 0), Intersection{})
 	assert.True(t, n.Equal(NewVector(1, 0, 0)))
 }
 
@@ -210,7 +210,7 @@ func TestNormalPointXAxis(t *testing.T) {
 */
 func TestNormalPointYAxis(t *testing.T) {
 	s := NewSphere()
-	n := s.NormalAt(NewPoint(0, 1, 0))
+	n := NormalAt(s, NewPoint(0, 1, 0), Intersection{})
 	assert.True(t, n.Equal(NewVector(0, 1, 0)))
 }
 
@@ -222,7 +222,7 @@ func TestNormalPointYAxis(t *testing.T) {
 */
 func TestNormalPointZAxis(t *testing.T) {
 	s := NewSphere()
-	n := s.NormalAt(NewPoint(0, 0, 1))
+	n := NormalAt(s, NewPoint(0, 0, 1), Intersection{})
 	assert.True(t, n.Equal(NewVector(0, 0, 1)))
 }
 
@@ -234,7 +234,7 @@ func TestNormalPointZAxis(t *testing.T) {
 */
 func TestNormalPointNonaxial(t *testing.T) {
 	s := NewSphere()
-	n := s.NormalAt(NewPoint(math.Sqrt(3)/3, math.Sqrt(3)/3, math.Sqrt(3)/3))
+	n := NormalAt(s, NewPoint(math.Sqrt(3)/3, math.Sqrt(3)/3, math.Sqrt(3)/3), Intersection{})
 	assert.True(t, n.Equal(NewVector(math.Sqrt(3)/3, math.Sqrt(3)/3, math.Sqrt(3)/3)))
 }
 
@@ -246,7 +246,7 @@ func TestNormalPointNonaxial(t *testing.T) {
 */
 func TestNormalPointIsNormalized(t *testing.T) {
 	s := NewSphere()
-	n := s.NormalAt(NewPoint(math.Sqrt(3)/3, math.Sqrt(3)/3, math.Sqrt(3)/3))
+	n := NormalAt(s, NewPoint(math.Sqrt(3)/3, math.Sqrt(3)/3, math.Sqrt(3)/3), Intersection{})
 	normd := n.Norm()
 	assert.True(t, n.Equal(normd))
 }
@@ -260,7 +260,7 @@ func TestNormalPointIsNormalized(t *testing.T) {
 */
 func TestNormalPointTranslatedSphere(t *testing.T) {
 	s := NewSphere().WithTransform(NewTranslation(0, 1, 0))
-	n := s.NormalAt(NewPoint(0, 1.70711, -0.70711))
+	n := NormalAt(s, NewPoint(0, 1.70711, -0.70711), Intersection{})
 	assert.True(t, n.Equal(NewVector(0, 0.70711, -0.70711)))
 }
 
@@ -274,7 +274,7 @@ func TestNormalPointTranslatedSphere(t *testing.T) {
 */
 func TestNormalPointTransformedSphere(t *testing.T) {
 	s := NewSphere().WithTransform(NewRotationZ(math.Pi/5).Scale(1, 0.5, 1))
-	n := s.NormalAt(NewPoint(0, math.Sqrt2/2, -math.Sqrt2/2))
+	n := NormalAt(s, NewPoint(0, math.Sqrt2/2, -math.Sqrt2/2), Intersection{})
 	assert.True(t, n.Equal(NewVector(0, 0.97014, -0.24254)))
 }
 
@@ -286,8 +286,8 @@ func TestNormalPointTransformedSphere(t *testing.T) {
 */
 func TestSphereDefaultMaterial(t *testing.T) {
 	s := NewSphere()
-	m := s.Material
-	assert.Equal(t, NewMaterial(), m)
+	m := s.Material()
+	assert.Equal(t, NewMaterial(), *m)
 }
 
 /*
@@ -302,8 +302,8 @@ func TestSphereAssignMaterial(t *testing.T) {
 	s := NewSphere()
 	m := NewMaterial()
 	m.Ambient = 1
-	s.Material = m
-	assert.Equal(t, m, s.Material)
+	s.SetMaterial(m)
+	assert.Equal(t, m, *s.Material())
 }
 
 /*
@@ -318,5 +318,29 @@ func TestSphereAssignMaterialFluid(t *testing.T) {
 	m := NewMaterial()
 	m.Ambient = 1
 	s := NewSphere().WithMaterial(m)
-	assert.Equal(t, m, s.Material)
+	assert.Equal(t, m, *s.Material())
+}
+
+/*
+	Scenario: A sphere is a shape
+	Given s ← sphere()
+	Then s is a shape
+*/
+func TestSphereIsShape(t *testing.T) {
+	var s Shape = NewSphere()
+	assert.Implements(t, (*Shape)(nil), s)
+}
+
+/*
+	Scenario: A helper for producing a sphere with a glassy material
+	Given s ← glass_sphere()
+	Then s.transform = identity_matrix
+	And s.material.transparency = 1.0
+	And s.material.refractive_index = 1.5
+*/
+func TestGlassSphere(t *testing.T) {
+	s := NewGlassSphere()
+	assert.Equal(t, NewIdentityMat4(), s.Transform())
+	assert.Equal(t, 1.0, s.Material().Transparency)
+	assert.Equal(t, 1.5, s.Material().RefractiveIndex)
 }
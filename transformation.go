@@ -4,24 +4,24 @@ import (
 	"math"
 )
 
-func NewTranslation(x, y, z float64) Matrix {
-	t := NewIdentityMatrix(4)
+func NewTranslation(x, y, z float64) Mat4 {
+	t := NewIdentityMat4()
 	t[0][3] = x
 	t[1][3] = y
 	t[2][3] = z
 	return t
 }
 
-func NewScaling(x, y, z float64) Matrix {
-	s := NewIdentityMatrix(4)
+func NewScaling(x, y, z float64) Mat4 {
+	s := NewIdentityMat4()
 	s[0][0] = x
 	s[1][1] = y
 	s[2][2] = z
 	return s
 }
 
-func NewRotationX(radians float64) Matrix {
-	r := NewIdentityMatrix(4)
+func NewRotationX(radians float64) Mat4 {
+	r := NewIdentityMat4()
 	r[1][1] = math.Cos(radians)
 	r[2][2] = math.Cos(radians)
 	r[1][2] = -math.Sin(radians)
@@ -29,8 +29,8 @@ func NewRotationX(radians float64) Matrix {
 	return r
 }
 
-func NewRotationY(radians float64) Matrix {
-	r := NewIdentityMatrix(4)
+func NewRotationY(radians float64) Mat4 {
+	r := NewIdentityMat4()
 	r[0][0] = math.Cos(radians)
 	r[2][2] = math.Cos(radians)
 	r[0][2] = math.Sin(radians)
@@ -38,8 +38,8 @@ func NewRotationY(radians float64) Matrix {
 	return r
 }
 
-func NewRotationZ(radians float64) Matrix {
-	r := NewIdentityMatrix(4)
+func NewRotationZ(radians float64) Mat4 {
+	r := NewIdentityMat4()
 	r[0][0] = math.Cos(radians)
 	r[1][1] = math.Cos(radians)
 	r[0][1] = -math.Sin(radians)
@@ -47,8 +47,8 @@ func NewRotationZ(radians float64) Matrix {
 	return r
 }
 
-func NewShearing(xy, xz, yx, yz, zx, zy float64) Matrix {
-	s := NewIdentityMatrix(4)
+func NewShearing(xy, xz, yx, yz, zx, zy float64) Mat4 {
+	s := NewIdentityMat4()
 	s[0][1] = xy
 	s[0][2] = xz
 	s[1][0] = yx
@@ -60,57 +60,57 @@ func NewShearing(xy, xz, yx, yz, zx, zy float64) Matrix {
 
 type Transform struct {
 	t Tuple
-	m Matrix
+	m Mat4
 }
 
 // method chaining
 // not very idiomatic, particularly here we encourage panic
 func NewTransform(t Tuple) Transform {
-	return Transform{t, NewIdentityMatrix(4)}
+	return Transform{t, NewIdentityMat4()}
 }
 
-func (m Matrix) Translate(x, y, z float64) Matrix {
-	return NewTranslation(x, y, z).MustMulM(m)
+func (m Mat4) Translate(x, y, z float64) Mat4 {
+	return NewTranslation(x, y, z).MulM(m)
 }
 
 func (c Transform) Translate(x, y, z float64) Transform {
 	return Transform{c.t, c.m.Translate(x, y, z)}
 }
 
-func (m Matrix) Scale(x, y, z float64) Matrix {
-	return NewScaling(x, y, z).MustMulM(m)
+func (m Mat4) Scale(x, y, z float64) Mat4 {
+	return NewScaling(x, y, z).MulM(m)
 }
 
 func (c Transform) Scale(x, y, z float64) Transform {
 	return Transform{c.t, c.m.Scale(x, y, z)}
 }
 
-func (m Matrix) RotateX(radians float64) Matrix {
-	return NewRotationX(radians).MustMulM(m)
+func (m Mat4) RotateX(radians float64) Mat4 {
+	return NewRotationX(radians).MulM(m)
 }
 
 func (c Transform) RotateX(radians float64) Transform {
 	return Transform{c.t, c.m.RotateX(radians)}
 }
 
-func (m Matrix) RotateY(radians float64) Matrix {
-	return NewRotationY(radians).MustMulM(m)
+func (m Mat4) RotateY(radians float64) Mat4 {
+	return NewRotationY(radians).MulM(m)
 }
 
 func (c Transform) RotateY(radians float64) Transform {
 	return Transform{c.t, c.m.RotateY(radians)}
 }
 
-func (m Matrix) RotateZ(radians float64) Matrix {
-	return NewRotationZ(radians).MustMulM(m)
+func (m Mat4) RotateZ(radians float64) Mat4 {
+	return NewRotationZ(radians).MulM(m)
 }
 
 func (c Transform) RotateZ(radians float64) Transform {
 	return Transform{c.t, c.m.RotateZ(radians)}
 }
 
-func (m Matrix) Shear(xy, xz, yx, yz, zx, zy float64) Matrix {
-	return NewShearing(xy, xz, yx, yz, zx, zy).MustMulM(m)
+func (m Mat4) Shear(xy, xz, yx, yz, zx, zy float64) Mat4 {
+	return NewShearing(xy, xz, yx, yz, zx, zy).MulM(m)
 }
 
 func (c Transform) Shear(xy, xz, yx, yz, zx, zy float64) Transform {
@@ -118,5 +118,19 @@ func (c Transform) Shear(xy, xz, yx, yz, zx, zy float64) Transform {
 }
 
 func (c Transform) Value() Tuple {
-	return c.m.MustMulT(c.t)
+	return c.m.MulT(c.t)
+}
+
+// ViewTransform - orient the world relative to an eye at from, looking at to
+func ViewTransform(from, to, up Tuple) Mat4 {
+	forward := to.Sub(from).Norm()
+	left := forward.Cross(up.Norm())
+	trueUp := left.Cross(forward)
+	orientation := Mat4{
+		{left.X, left.Y, left.Z, 0},
+		{trueUp.X, trueUp.Y, trueUp.Z, 0},
+		{-forward.X, -forward.Y, -forward.Z, 0},
+		{0, 0, 0, 1},
+	}
+	return orientation.MulM(NewTranslation(-from.X, -from.Y, -from.Z))
 }
@@ -17,8 +17,7 @@ import (
 func TestMulTranslationMatrix(t *testing.T) {
 	transform := NewTranslation(5, -3, 2)
 	p := NewPoint(-3, 4, 5)
-	actual, err := transform.MulT(p)
-	require.Nil(t, err)
+	actual := transform.MulT(p)
 	expected := NewPoint(2, 1, 7)
 	assert.Equal(t, expected, actual)
 }
@@ -35,8 +34,7 @@ func TestMulTranslationMatrixInverse(t *testing.T) {
 	inv, err := transform.Inverse()
 	require.Nil(t, err)
 	p := NewPoint(-3, 4, 5)
-	actual, err := inv.MulT(p)
-	require.Nil(t, err)
+	actual := inv.MulT(p)
 	expected := NewPoint(-8, 7, 3)
 	assert.Equal(t, expected, actual)
 }
@@ -50,8 +48,7 @@ func TestMulTranslationMatrixInverse(t *testing.T) {
 func TestMulTranslationNotAffectVectors(t *testing.T) {
 	transform := NewTranslation(5, -3, 2)
 	v := NewVector(-3, 4, 5)
-	actual, err := transform.MulT(v)
-	require.Nil(t, err)
+	actual := transform.MulT(v)
 	assert.Equal(t, v, actual)
 }
 
@@ -64,8 +61,7 @@ func TestMulTranslationNotAffectVectors(t *testing.T) {
 func TestMulScalingMatrixPoint(t *testing.T) {
 	transform := NewScaling(2, 3, 4)
 	p := NewPoint(-4, 6, 8)
-	actual, err := transform.MulT(p)
-	require.Nil(t, err)
+	actual := transform.MulT(p)
 	expected := NewPoint(-8, 18, 32)
 	assert.Equal(t, expected, actual)
 }
@@ -79,8 +75,7 @@ func TestMulScalingMatrixPoint(t *testing.T) {
 func TestMulScalingMatrixVector(t *testing.T) {
 	transform := NewScaling(2, 3, 4)
 	v := NewVector(-4, 6, 8)
-	actual, err := transform.MulT(v)
-	require.Nil(t, err)
+	actual := transform.MulT(v)
 	expected := NewVector(-8, 18, 32)
 	assert.Equal(t, expected, actual)
 }
@@ -97,8 +92,7 @@ func TestMulScalingMatrixInverse(t *testing.T) {
 	inv, err := transform.Inverse()
 	require.Nil(t, err)
 	v := NewVector(-4, 6, 8)
-	actual, err := inv.MulT(v)
-	require.Nil(t, err)
+	actual := inv.MulT(v)
 	expected := NewVector(-2, 2, 2)
 	assert.Equal(t, expected, actual)
 }
@@ -112,8 +106,7 @@ func TestMulScalingMatrixInverse(t *testing.T) {
 func TestReflectNegativeScaling(t *testing.T) {
 	transform := NewScaling(-1, 1, 1)
 	p := NewPoint(2, 3, 4)
-	actual, err := transform.MulT(p)
-	require.Nil(t, err)
+	actual := transform.MulT(p)
 	expected := NewPoint(-2, 3, 4)
 	assert.Equal(t, expected, actual)
 }
@@ -130,10 +123,8 @@ func TestRotateAroundX(t *testing.T) {
 	p := NewPoint(0, 1, 0)
 	halfQuarter := NewRotationX(math.Pi / 4)
 	fullQuarter := NewRotationX(math.Pi / 2)
-	hqa, err := halfQuarter.MulT(p)
-	require.Nil(t, err)
-	fqa, err := fullQuarter.MulT(p)
-	require.Nil(t, err)
+	hqa := halfQuarter.MulT(p)
+	fqa := fullQuarter.MulT(p)
 	hqe := NewPoint(0, math.Sqrt2/2, math.Sqrt2/2)
 	fqe := NewPoint(0, 0, 1)
 	assert.True(t, hqe.Equal(hqa))
@@ -152,8 +143,7 @@ func TestInverseRotateX(t *testing.T) {
 	halfQuarter := NewRotationX(math.Pi / 4)
 	inv, err := halfQuarter.Inverse()
 	require.Nil(t, err)
-	actual, err := inv.MulT(p)
-	require.Nil(t, err)
+	actual := inv.MulT(p)
 	expected := NewPoint(0, math.Sqrt2/2, -math.Sqrt2/2)
 	assert.True(t, expected.Equal(actual))
 }
@@ -170,10 +160,8 @@ func TestRotateAroundY(t *testing.T) {
 	p := NewPoint(0, 0, 1)
 	halfQuarter := NewRotationY(math.Pi / 4)
 	fullQuarter := NewRotationY(math.Pi / 2)
-	hqa, err := halfQuarter.MulT(p)
-	require.Nil(t, err)
-	fqa, err := fullQuarter.MulT(p)
-	require.Nil(t, err)
+	hqa := halfQuarter.MulT(p)
+	fqa := fullQuarter.MulT(p)
 	hqe := NewPoint(math.Sqrt2/2, 0, math.Sqrt2/2)
 	fqe := NewPoint(1, 0, 0)
 	assert.True(t, hqe.Equal(hqa))
@@ -192,10 +180,8 @@ func TestRotateAroundZ(t *testing.T) {
 	p := NewPoint(0, 1, 0)
 	halfQuarter := NewRotationZ(math.Pi / 4)
 	fullQuarter := NewRotationZ(math.Pi / 2)
-	hqa, err := halfQuarter.MulT(p)
-	require.Nil(t, err)
-	fqa, err := fullQuarter.MulT(p)
-	require.Nil(t, err)
+	hqa := halfQuarter.MulT(p)
+	fqa := fullQuarter.MulT(p)
 	hqe := NewPoint(-math.Sqrt2/2, math.Sqrt2/2, 0)
 	fqe := NewPoint(-1, 0, 0)
 	assert.True(t, hqe.Equal(hqa))
@@ -211,8 +197,7 @@ func TestRotateAroundZ(t *testing.T) {
 func TestShearXPropY(t *testing.T) {
 	transform := NewShearing(1, 0, 0, 0, 0, 0)
 	p := NewPoint(2, 3, 4)
-	actual, err := transform.MulT(p)
-	require.Nil(t, err)
+	actual := transform.MulT(p)
 	expected := NewPoint(5, 3, 4)
 	assert.True(t, expected.Equal(actual))
 }
@@ -226,8 +211,7 @@ func TestShearXPropY(t *testing.T) {
 func TestShearXPropZ(t *testing.T) {
 	transform := NewShearing(0, 1, 0, 0, 0, 0)
 	p := NewPoint(2, 3, 4)
-	actual, err := transform.MulT(p)
-	require.Nil(t, err)
+	actual := transform.MulT(p)
 	expected := NewPoint(6, 3, 4)
 	assert.True(t, expected.Equal(actual))
 }
@@ -241,8 +225,7 @@ func TestShearXPropZ(t *testing.T) {
 func TestShearYPropX(t *testing.T) {
 	transform := NewShearing(0, 0, 1, 0, 0, 0)
 	p := NewPoint(2, 3, 4)
-	actual, err := transform.MulT(p)
-	require.Nil(t, err)
+	actual := transform.MulT(p)
 	expected := NewPoint(2, 5, 4)
 	assert.True(t, expected.Equal(actual))
 }
@@ -256,8 +239,7 @@ func TestShearYPropX(t *testing.T) {
 func TestShearYPropz(t *testing.T) {
 	transform := NewShearing(0, 0, 0, 1, 0, 0)
 	p := NewPoint(2, 3, 4)
-	actual, err := transform.MulT(p)
-	require.Nil(t, err)
+	actual := transform.MulT(p)
 	expected := NewPoint(2, 7, 4)
 	assert.True(t, expected.Equal(actual))
 }
@@ -271,8 +253,7 @@ func TestShearYPropz(t *testing.T) {
 func TestShearZPropX(t *testing.T) {
 	transform := NewShearing(0, 0, 0, 0, 1, 0)
 	p := NewPoint(2, 3, 4)
-	actual, err := transform.MulT(p)
-	require.Nil(t, err)
+	actual := transform.MulT(p)
 	expected := NewPoint(2, 3, 6)
 	assert.True(t, expected.Equal(actual))
 }
@@ -286,8 +267,7 @@ func TestShearZPropX(t *testing.T) {
 func TestShearZPropY(t *testing.T) {
 	transform := NewShearing(0, 0, 0, 0, 0, 1)
 	p := NewPoint(2, 3, 4)
-	actual, err := transform.MulT(p)
-	require.Nil(t, err)
+	actual := transform.MulT(p)
 	expected := NewPoint(2, 3, 7)
 	assert.True(t, expected.Equal(actual))
 }
@@ -315,18 +295,15 @@ func TestIndividualTransformsAppliedInSequence(t *testing.T) {
 	C := NewTranslation(10, 5, 7)
 
 	// apply first rotation
-	p2, err := A.MulT(p)
-	require.Nil(t, err)
+	p2 := A.MulT(p)
 	assert.True(t, p2.Equal(NewPoint(1, -1, 0)))
 
 	// then apply scaling
-	p3, err := B.MulT(p2)
-	require.Nil(t, err)
+	p3 := B.MulT(p2)
 	assert.True(t, p3.Equal(NewPoint(5, -5, 0)))
 
 	// then apply translation
-	p4, err := C.MulT(p3)
-	require.Nil(t, err)
+	p4 := C.MulT(p3)
 	assert.True(t, p4.Equal(NewPoint(15, 0, 7)))
 }
 
@@ -345,9 +322,8 @@ func TestChainedTransformationReverseOrder(t *testing.T) {
 	B := NewScaling(5, 5, 5)
 	C := NewTranslation(10, 5, 7)
 
-	T := C.MustMulM(B).MustMulM(A)
-	Tp, err := T.MulT(p)
-	require.Nil(t, err)
+	T := C.MulM(B).MulM(A)
+	Tp := T.MulT(p)
 
 	assert.Equal(t, Tp, NewPoint(15, 0, 7))
 }
@@ -370,3 +346,79 @@ func TestFluidApiMethodChainOrder(t *testing.T) {
 
 	assert.Equal(t, tp, NewPoint(15, 0, 7))
 }
+
+/*
+	Scenario: The transformation matrix for the default orientation
+	Given from ← point(0, 0, 0)
+	And to ← point(0, 0, -1)
+	And up ← vector(0, 1, 0)
+	When t ← view_transform(from, to, up)
+	Then t = identity_matrix
+*/
+func TestViewTransformDefaultOrientation(t *testing.T) {
+	from := NewPoint(0, 0, 0)
+	to := NewPoint(0, 0, -1)
+	up := NewVector(0, 1, 0)
+	tr := ViewTransform(from, to, up)
+	assert.Equal(t, NewIdentityMat4(), tr)
+}
+
+/*
+	Scenario: A view transformation matrix looking in positive z direction
+	Given from ← point(0, 0, 0)
+	And to ← point(0, 0, 1)
+	And up ← vector(0, 1, 0)
+	When t ← view_transform(from, to, up)
+	Then t = scaling(-1, 1, -1)
+*/
+func TestViewTransformPositiveZ(t *testing.T) {
+	from := NewPoint(0, 0, 0)
+	to := NewPoint(0, 0, 1)
+	up := NewVector(0, 1, 0)
+	tr := ViewTransform(from, to, up)
+	assert.Equal(t, NewScaling(-1, 1, -1), tr)
+}
+
+/*
+	Scenario: The view transformation moves the world
+	Given from ← point(0, 0, 8)
+	And to ← point(0, 0, 0)
+	And up ← vector(0, 1, 0)
+	When t ← view_transform(from, to, up)
+	Then t = translation(0, 0, -8)
+*/
+func TestViewTransformMovesWorld(t *testing.T) {
+	from := NewPoint(0, 0, 8)
+	to := NewPoint(0, 0, 0)
+	up := NewVector(0, 1, 0)
+	tr := ViewTransform(from, to, up)
+	assert.Equal(t, NewTranslation(0, 0, -8), tr)
+}
+
+/*
+	Scenario: An arbitrary view transformation
+	Given from ← point(1, 3, 2)
+	And to ← point(4, -2, 8)
+	And up ← vector(1, 1, 0)
+	When t ← view_transform(from, to, up)
+	Then t is the following 4x4 matrix:
+		| -0.50709 | 0.50709 |  0.67612 | -2.36643 |
+		|  0.76772 | 0.60609 |  0.12122 | -2.82843 |
+		| -0.35857 | 0.59761 | -0.71714 |  0.00000 |
+		|  0.00000 | 0.00000 |  0.00000 |  1.00000 |
+*/
+func TestViewTransformArbitrary(t *testing.T) {
+	from := NewPoint(1, 3, 2)
+	to := NewPoint(4, -2, 8)
+	up := NewVector(1, 1, 0)
+	tr := ViewTransform(from, to, up)
+	expected := Mat4{
+		{-0.50709, 0.50709, 0.67612, -2.36643},
+		{0.76772, 0.60609, 0.12122, -2.82843},
+		{-0.35857, 0.59761, -0.71714, 0.00000},
+		{0.00000, 0.00000, 0.00000, 1.00000},
+	}
+	for i := range expected {
+		assert.InDeltaSlice(t, expected[i][:], tr[i][:], 0.0001)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"math"
+)
+
+// Triangle - flat triangle between three points
+type Triangle struct {
+	shape
+	P1     Tuple
+	P2     Tuple
+	P3     Tuple
+	E1     Tuple
+	E2     Tuple
+	Normal Tuple
+}
+
+func NewTriangle(p1, p2, p3 Tuple) *Triangle {
+	e1 := p2.Sub(p1)
+	e2 := p3.Sub(p1)
+	return &Triangle{
+		shape:  newShape(),
+		P1:     p1,
+		P2:     p2,
+		P3:     p3,
+		E1:     e1,
+		E2:     e2,
+		Normal: e2.Cross(e1).Norm(),
+	}
+}
+
+func (tr *Triangle) WithTransform(t Mat4) *Triangle {
+	tr.SetTransform(t)
+	return tr
+}
+
+func (tr *Triangle) WithMaterial(m Material) *Triangle {
+	tr.SetMaterial(m)
+	return tr
+}
+
+// intersectTriangle - möller-trumbore ray/triangle intersection, returning
+// t and the barycentric u, v of the hit
+func intersectTriangle(r Ray, p1, e1, e2 Tuple) (t, u, v float64, ok bool) {
+	dirCrossE2 := r.Direction.Cross(e2)
+	det := e1.Dot(dirCrossE2)
+	// parallel to the plane of the triangle
+	if math.Abs(det) < epsilon {
+		return 0, 0, 0, false
+	}
+
+	f := 1.0 / det
+	p1ToOrigin := r.Origin.Sub(p1)
+	u = f * p1ToOrigin.Dot(dirCrossE2)
+	if u < 0 || u > 1 {
+		return 0, 0, 0, false
+	}
+
+	originCrossE1 := p1ToOrigin.Cross(e1)
+	v = f * r.Direction.Dot(originCrossE1)
+	if v < 0 || u+v > 1 {
+		return 0, 0, 0, false
+	}
+
+	t = f * e2.Dot(originCrossE1)
+	return t, u, v, true
+}
+
+func (tr *Triangle) LocalIntersect(r Ray) Intersections {
+	t, u, v, ok := intersectTriangle(r, tr.P1, tr.E1, tr.E2)
+	if !ok {
+		return Intersections{}
+	}
+	return Intersections{NewIntersectionWithUV(t, tr, u, v)}
+}
+
+func (tr *Triangle) LocalNormalAt(_ Tuple, _ Intersection) Tuple {
+	return tr.Normal
+}
+
+func (tr *Triangle) Bounds() BoundingBox {
+	return NewBoundingBox().AddPoint(tr.P1).AddPoint(tr.P2).AddPoint(tr.P3)
+}
+
+// SmoothTriangle - triangle with a normal per vertex, interpolated across the face
+type SmoothTriangle struct {
+	shape
+	P1 Tuple
+	P2 Tuple
+	P3 Tuple
+	N1 Tuple
+	N2 Tuple
+	N3 Tuple
+	E1 Tuple
+	E2 Tuple
+}
+
+func NewSmoothTriangle(p1, p2, p3, n1, n2, n3 Tuple) *SmoothTriangle {
+	return &SmoothTriangle{
+		shape: newShape(),
+		P1:    p1,
+		P2:    p2,
+		P3:    p3,
+		N1:    n1,
+		N2:    n2,
+		N3:    n3,
+		E1:    p2.Sub(p1),
+		E2:    p3.Sub(p1),
+	}
+}
+
+func (tr *SmoothTriangle) WithTransform(t Mat4) *SmoothTriangle {
+	tr.SetTransform(t)
+	return tr
+}
+
+func (tr *SmoothTriangle) WithMaterial(m Material) *SmoothTriangle {
+	tr.SetMaterial(m)
+	return tr
+}
+
+func (tr *SmoothTriangle) LocalIntersect(r Ray) Intersections {
+	t, u, v, ok := intersectTriangle(r, tr.P1, tr.E1, tr.E2)
+	if !ok {
+		return Intersections{}
+	}
+	return Intersections{NewIntersectionWithUV(t, tr, u, v)}
+}
+
+func (tr *SmoothTriangle) LocalNormalAt(_ Tuple, hit Intersection) Tuple {
+	return tr.N2.Mul(hit.U).
+		Add(tr.N3.Mul(hit.V)).
+		Add(tr.N1.Mul(1 - hit.U - hit.V))
+}
+
+func (tr *SmoothTriangle) Bounds() BoundingBox {
+	return NewBoundingBox().AddPoint(tr.P1).AddPoint(tr.P2).AddPoint(tr.P3)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario: Constructing a triangle
+	Given p1 ← point(0, 1, 0)
+	And p2 ← point(-1, 0, 0)
+	And p3 ← point(1, 0, 0)
+	And t ← triangle(p1, p2, p3)
+	Then t.p1 = p1
+	And t.p2 = p2
+	And t.p3 = p3
+	And t.e1 = vector(-1, -1, 0)
+	And t.e2 = vector(1, -1, 0)
+	And t.normal = vector(0, 0, -1)
+*/
+func TestCreateTriangle(t *testing.T) {
+	p1 := NewPoint(0, 1, 0)
+	p2 := NewPoint(-1, 0, 0)
+	p3 := NewPoint(1, 0, 0)
+	tr := NewTriangle(p1, p2, p3)
+	assert.Equal(t, p1, tr.P1)
+	assert.Equal(t, p2, tr.P2)
+	assert.Equal(t, p3, tr.P3)
+	assert.True(t, tr.E1.Equal(NewVector(-1, -1, 0)))
+	assert.True(t, tr.E2.Equal(NewVector(1, -1, 0)))
+	assert.True(t, tr.Normal.Equal(NewVector(0, 0, -1)))
+}
+
+/*
+	Scenario: Finding the normal on a triangle
+	Given t ← triangle(point(0, 1, 0), point(-1, 0, 0), point(1, 0, 0))
+	When n1 ← local_normal_at(t, point(0, 0.5, 0))
+	And n2 ← local_normal_at(t, point(-0.5, 0.75, 0))
+	And n3 ← local_normal_at(t, point(0.5, 0.25, 0))
+	Then n1 = t.normal
+	And n2 = t.normal
+	And n3 = t.normal
+*/
+func TestTriangleNormal(t *testing.T) {
+	tr := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+	n1 := tr.LocalNormalAt(NewPoint(0, 0.5, 0), Intersection{})
+	n2 := tr.LocalNormalAt(NewPoint(-0.5, 0.75, 0), Intersection{})
+	n3 := tr.LocalNormalAt(NewPoint(0.5, 0.25, 0), Intersection{})
+	assert.Equal(t, tr.Normal, n1)
+	assert.Equal(t, tr.Normal, n2)
+	assert.Equal(t, tr.Normal, n3)
+}
+
+/*
+	Scenario: Intersecting a ray parallel to the triangle
+	Given t ← triangle(point(0, 1, 0), point(-1, 0, 0), point(1, 0, 0))
+	And r ← ray(point(0, -1, -2), vector(0, 1, 0))
+	When xs ← local_intersect(t, r)
+	Then xs is empty
+*/
+func TestIntersectRayParallelToTriangle(t *testing.T) {
+	tr := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+	r := Ray{NewPoint(0, -1, -2), NewVector(0, 1, 0)}
+	xs := tr.LocalIntersect(r)
+	assert.Equal(t, 0, len(xs))
+}
+
+/*
+	Scenario: A ray misses the p1-p3 edge
+	Given t ← triangle(point(0, 1, 0), point(-1, 0, 0), point(1, 0, 0))
+	And r ← ray(point(1, 1, -2), vector(0, 0, 1))
+	When xs ← local_intersect(t, r)
+	Then xs is empty
+*/
+func TestRayMissesTriangleP1P3Edge(t *testing.T) {
+	tr := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+	r := Ray{NewPoint(1, 1, -2), NewVector(0, 0, 1)}
+	xs := tr.LocalIntersect(r)
+	assert.Equal(t, 0, len(xs))
+}
+
+/*
+	Scenario: A ray misses the p1-p2 edge
+	Given t ← triangle(point(0, 1, 0), point(-1, 0, 0), point(1, 0, 0))
+	And r ← ray(point(-1, 1, -2), vector(0, 0, 1))
+	When xs ← local_intersect(t, r)
+	Then xs is empty
+*/
+func TestRayMissesTriangleP1P2Edge(t *testing.T) {
+	tr := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+	r := Ray{NewPoint(-1, 1, -2), NewVector(0, 0, 1)}
+	xs := tr.LocalIntersect(r)
+	assert.Equal(t, 0, len(xs))
+}
+
+/*
+	Scenario: A ray misses the p2-p3 edge
+	Given t ← triangle(point(0, 1, 0), point(-1, 0, 0), point(1, 0, 0))
+	And r ← ray(point(0, -1, -2), vector(0, 0, 1))
+	When xs ← local_intersect(t, r)
+	Then xs is empty
+*/
+func TestRayMissesTriangleP2P3Edge(t *testing.T) {
+	tr := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+	r := Ray{NewPoint(0, -1, -2), NewVector(0, 0, 1)}
+	xs := tr.LocalIntersect(r)
+	assert.Equal(t, 0, len(xs))
+}
+
+/*
+	Scenario: A ray strikes a triangle
+	Given t ← triangle(point(0, 1, 0), point(-1, 0, 0), point(1, 0, 0))
+	And r ← ray(point(0, 0.5, -2), vector(0, 0, 1))
+	When xs ← local_intersect(t, r)
+	Then xs.count = 1
+	And xs[0].t = 2
+*/
+func TestRayStrikesTriangle(t *testing.T) {
+	tr := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+	r := Ray{NewPoint(0, 0.5, -2), NewVector(0, 0, 1)}
+	xs := tr.LocalIntersect(r)
+	require.Equal(t, 1, len(xs))
+	assert.InDelta(t, 2.0, xs[0].T, epsilon)
+}
+
+/*
+	Background:
+	Given p1 ← point(0, 1, 0)
+	And p2 ← point(-1, 0, 0)
+	And p3 ← point(1, 0, 0)
+	And n1 ← vector(0, 1, 0)
+	And n2 ← vector(-1, 0, 0)
+	And n3 ← vector(1, 0, 0)
+	When tri ← smooth_triangle(p1, p2, p3, n1, n2, n3)
+*/
+func newTestSmoothTriangle() *SmoothTriangle {
+	return NewSmoothTriangle(
+		NewPoint(0, 1, 0),
+		NewPoint(-1, 0, 0),
+		NewPoint(1, 0, 0),
+		NewVector(0, 1, 0),
+		NewVector(-1, 0, 0),
+		NewVector(1, 0, 0),
+	)
+}
+
+/*
+	Scenario: Constructing a smooth triangle
+	Then tri.p1 = p1
+	And tri.p2 = p2
+	And tri.p3 = p3
+	And tri.n1 = n1
+	And tri.n2 = n2
+	And tri.n3 = n3
+*/
+func TestCreateSmoothTriangle(t *testing.T) {
+	tri := newTestSmoothTriangle()
+	assert.Equal(t, NewPoint(0, 1, 0), tri.P1)
+	assert.Equal(t, NewPoint(-1, 0, 0), tri.P2)
+	assert.Equal(t, NewPoint(1, 0, 0), tri.P3)
+	assert.Equal(t, NewVector(0, 1, 0), tri.N1)
+	assert.Equal(t, NewVector(-1, 0, 0), tri.N2)
+	assert.Equal(t, NewVector(1, 0, 0), tri.N3)
+}
+
+/*
+	Scenario: An intersection can encapsulate `u` and `v`
+	Given s ← triangle(point(0, 1, 0), point(-1, 0, 0), point(1, 0, 0))
+	When i ← intersection_with_uv(3.5, s, 0.2, 0.4)
+	Then i.u = 0.2
+	And i.v = 0.4
+*/
+func TestIntersectionWithUV(t *testing.T) {
+	s := NewTriangle(NewPoint(0, 1, 0), NewPoint(-1, 0, 0), NewPoint(1, 0, 0))
+	i := NewIntersectionWithUV(3.5, s, 0.2, 0.4)
+	assert.Equal(t, 0.2, i.U)
+	assert.Equal(t, 0.4, i.V)
+}
+
+/*
+	Scenario: An intersection with a smooth triangle stores u/v
+	When r ← ray(point(-0.2, 0.3, -2), vector(0, 0, 1))
+	And xs ← local_intersect(tri, r)
+	Then xs[0].u = 0.45
+	And xs[0].v = 0.25
+*/
+func TestIntersectSmoothTriangleStoresUV(t *testing.T) {
+	tri := newTestSmoothTriangle()
+	r := Ray{NewPoint(-0.2, 0.3, -2), NewVector(0, 0, 1)}
+	xs := tri.LocalIntersect(r)
+	require.Equal(t, 1, len(xs))
+	assert.InDelta(t, 0.45, xs[0].U, epsilon)
+	assert.InDelta(t, 0.25, xs[0].V, epsilon)
+}
+
+/*
+	Scenario: A smooth triangle uses u/v to interpolate the normal
+	When i ← intersection_with_uv(1, tri, 0.45, 0.25)
+	And n ← normal_at(tri, point(0, 0, 0), i)
+	Then n = vector(-0.5547, 0.83205, 0)
+*/
+func TestSmoothTriangleInterpolatesNormal(t *testing.T) {
+	tri := newTestSmoothTriangle()
+	i := NewIntersectionWithUV(1, tri, 0.45, 0.25)
+	n := NormalAt(tri, NewPoint(0, 0, 0), i)
+	assert.True(t, n.Equal(NewVector(-0.5547, 0.83205, 0)))
+}
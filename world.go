@@ -0,0 +1,141 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultMaxDepth - how many times NewWorld lets a ray bounce off or pass through surfaces
+const DefaultMaxDepth = 5
+
+// World - collection of objects and the lights that illuminate them
+type World struct {
+	Objects []Shape
+	Lights  []PointLight
+	// MaxDepth - how many reflected or refracted rays can be spawned one
+	// after another from a single camera ray, stops mirrors facing each other
+	// recursing forever. 0 turns reflection and refraction off
+	MaxDepth int
+}
+
+// NewWorld - empty world, no objects and no lights
+func NewWorld() World {
+	return World{MaxDepth: DefaultMaxDepth}
+}
+
+// Intersect - intersect a ray with every object in the world, sorted by t
+func (w World) Intersect(r Ray) (Intersections, error) {
+	xs := Intersections{}
+	for _, o := range w.Objects {
+		oxs, err := Intersect(o, r)
+		if err != nil {
+			return nil, err
+		}
+		xs = append(xs, oxs...)
+	}
+	sort.Sort(xs)
+	return xs, nil
+}
+
+// ShadeHit - color at a hit, summed over every light, plus whatever the
+// surface reflects and lets through. remaining is how many more reflected or
+// refracted rays may be spawned
+func (w World) ShadeHit(comps Computations, remaining int) Color {
+	m := comps.Object.Material()
+	color := Black
+	for _, light := range w.Lights {
+		inShadow := w.IsShadowed(light, comps.OverPoint)
+		color = color.Add(m.Lighting(comps.Object, light, comps.OverPoint, comps.EyeV, comps.NormalV, inShadow))
+	}
+
+	reflected := w.ReflectedColor(comps, remaining)
+	refracted := w.RefractedColor(comps, remaining)
+	if m.Reflective > 0 && m.Transparency > 0 {
+		// glass reflects more the closer to edge on it's seen
+		reflectance := Schlick(comps)
+		return color.Add(reflected.MulS(reflectance)).Add(refracted.MulS(1 - reflectance))
+	}
+	return color.Add(reflected).Add(refracted)
+}
+
+// ReflectedColor - color the surface reflects back towards the eye, black
+// for materials that aren't reflective or when no more rays may be spawned
+func (w World) ReflectedColor(comps Computations, remaining int) Color {
+	reflective := comps.Object.Material().Reflective
+	if reflective == 0 || remaining < 1 {
+		return Black
+	}
+	reflectRay := Ray{comps.OverPoint, comps.ReflectV}
+	return w.colorAt(reflectRay, remaining-1).MulS(reflective)
+}
+
+// RefractedColor - color seen through the surface, bent by Snell's law.
+// Black for opaque materials, under total internal reflection or when no
+// more rays may be spawned
+func (w World) RefractedColor(comps Computations, remaining int) Color {
+	transparency := comps.Object.Material().Transparency
+	if transparency == 0 || remaining < 1 {
+		return Black
+	}
+
+	ratio := comps.N1 / comps.N2
+	cosI := comps.EyeV.Dot(comps.NormalV)
+	sin2T := square(ratio) * (1 - square(cosI))
+	if sin2T > 1 {
+		return Black
+	}
+	cosT := math.Sqrt(1 - sin2T)
+	direction := comps.NormalV.Mul(ratio*cosI - cosT).Sub(comps.EyeV.Mul(ratio))
+
+	// the refracted ray starts just inside the surface
+	refractRay := Ray{comps.UnderPoint, direction}
+	return w.colorAt(refractRay, remaining-1).MulS(transparency)
+}
+
+// Schlick - Schlick's approximation of the Fresnel equations, the fraction of
+// light reflected at the hit as the ray passes from N1 into N2
+func Schlick(comps Computations) float64 {
+	cos := comps.EyeV.Dot(comps.NormalV)
+	if comps.N1 > comps.N2 {
+		// past the critical angle everything is reflected
+		sin2T := square(comps.N1/comps.N2) * (1 - square(cos))
+		if sin2T > 1 {
+			return 1
+		}
+		cos = math.Sqrt(1 - sin2T)
+	}
+	r0 := square((comps.N1 - comps.N2) / (comps.N1 + comps.N2))
+	return r0 + (1-r0)*math.Pow(1-cos, 5)
+}
+
+// IsShadowed - whether any object sits between the point and the light
+func (w World) IsShadowed(light PointLight, point Tuple) bool {
+	v := light.Position.Sub(point)
+	distance := v.Mag()
+	r := Ray{point, v.Norm()}
+
+	xs, err := w.Intersect(r)
+	if err != nil {
+		panic(err)
+	}
+	hit := xs.Hit()
+	return hit != nil && hit.T < distance
+}
+
+// ColorAt - color seen by the given ray, black if it hits nothing
+func (w World) ColorAt(r Ray) Color {
+	return w.colorAt(r, w.MaxDepth)
+}
+
+// colorAt - ColorAt allowing remaining more reflected or refracted rays
+func (w World) colorAt(r Ray, remaining int) Color {
+	xs, err := w.Intersect(r)
+	if err != nil {
+		panic(err)
+	}
+	hit := xs.Hit()
+	if hit == nil {
+		return Black
+	}
+	return w.ShadeHit(PrepareComputations(*hit, r, xs), remaining)
+}
@@ -0,0 +1,753 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Scenario: The default world
+	Given light ← point_light(point(-10, 10, -10), color(1, 1, 1))
+	And s1 ← sphere() with:
+		| material.color     | (0.8, 1.0, 0.6)        |
+		| material.diffuse   | 0.7                    |
+		| material.specular  | 0.2                    |
+	And s2 ← sphere() with:
+		| transform | scaling(0.5, 0.5, 0.5) |
+	When w ← default_world()
+	Then w.light = light
+	And w contains s1
+	And w contains s2
+*/
+func defaultWorld() World {
+	light := PointLight{NewPoint(-10, 10, -10), Color{1, 1, 1}}
+
+	m := NewMaterial()
+	m.Color = Color{0.8, 1.0, 0.6}
+	m.Diffuse = 0.7
+	m.Specular = 0.2
+	s1 := NewSphere().WithMaterial(m)
+
+	s2 := NewSphere().WithTransform(NewScaling(0.5, 0.5, 0.5))
+
+	w := NewWorld()
+	w.Objects = []Shape{s1, s2}
+	w.Lights = []PointLight{light}
+	return w
+}
+
+/*
+	Scenario: Creating a world
+	Given w ← world()
+	Then w contains no objects
+	And w has no light source
+*/
+func TestCreateWorld(t *testing.T) {
+	w := NewWorld()
+	assert.Equal(t, 0, len(w.Objects))
+	assert.Equal(t, 0, len(w.Lights))
+	assert.Equal(t, DefaultMaxDepth, w.MaxDepth)
+}
+
+/*
+	Scenario: Intersect a world with a ray
+	Given w ← default_world()
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	When xs ← intersect_world(w, r)
+	Then xs.count = 4
+	And xs[0].t = 4
+	And xs[1].t = 4.5
+	And xs[2].t = 5.5
+	And xs[3].t = 6
+*/
+func TestIntersectWorld(t *testing.T) {
+	w := defaultWorld()
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	xs, err := w.Intersect(r)
+	require.Nil(t, err)
+	require.Equal(t, 4, len(xs))
+	assert.Equal(t, 4.0, xs[0].T)
+	assert.Equal(t, 4.5, xs[1].T)
+	assert.Equal(t, 5.5, xs[2].T)
+	assert.Equal(t, 6.0, xs[3].T)
+}
+
+/*
+	Scenario: Shading an intersection
+	Given w ← default_world()
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And shape ← the first object in w
+	And i ← intersection(4, shape)
+	When comps ← prepare_computations(i, r)
+	And c ← shade_hit(w, comps)
+	Then c = color(0.38066, 0.47583, 0.2855)
+*/
+func TestShadeHit(t *testing.T) {
+	w := defaultWorld()
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	i := NewIntersection(4, w.Objects[0])
+	comps := PrepareComputations(i, r, Intersections{i})
+	c := w.ShadeHit(comps, DefaultMaxDepth)
+	assert.True(t, c.Equal(Color{0.38066, 0.47583, 0.2855}))
+}
+
+/*
+	Scenario: Shading an intersection from the inside
+	Given w ← default_world()
+	And w.light ← point_light(point(0, 0.25, 0), color(1, 1, 1))
+	And r ← ray(point(0, 0, 0), vector(0, 0, 1))
+	And shape ← the second object in w
+	And i ← intersection(0.5, shape)
+	When comps ← prepare_computations(i, r)
+	And c ← shade_hit(w, comps)
+	Then c = color(0.90498, 0.90498, 0.90498)
+*/
+func TestShadeHitInside(t *testing.T) {
+	w := defaultWorld()
+	w.Lights = []PointLight{{NewPoint(0, 0.25, 0), Color{1, 1, 1}}}
+	r := Ray{NewPoint(0, 0, 0), NewVector(0, 0, 1)}
+	i := NewIntersection(0.5, w.Objects[1])
+	comps := PrepareComputations(i, r, Intersections{i})
+	c := w.ShadeHit(comps, DefaultMaxDepth)
+	assert.True(t, c.Equal(Color{0.90498, 0.90498, 0.90498}))
+}
+
+/*
+	Scenario: Shading an intersection with more than one light
+	Given w ← default_world()
+	And w has a second light identical to the first
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And shape ← the first object in w
+	And i ← intersection(4, shape)
+	When comps ← prepare_computations(i, r)
+	And c ← shade_hit(w, comps)
+	Then c = 2 * color(0.38066, 0.47583, 0.2855)
+*/
+func TestShadeHitMultipleLights(t *testing.T) {
+	w := defaultWorld()
+	w.Lights = append(w.Lights, w.Lights[0])
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	i := NewIntersection(4, w.Objects[0])
+	comps := PrepareComputations(i, r, Intersections{i})
+	c := w.ShadeHit(comps, DefaultMaxDepth)
+	assert.True(t, c.Equal(Color{0.38066, 0.47583, 0.2855}.MulS(2)))
+}
+
+/*
+	Scenario: The color when a ray misses
+	Given w ← default_world()
+	And r ← ray(point(0, 0, -5), vector(0, 1, 0))
+	When c ← color_at(w, r)
+	Then c = color(0, 0, 0)
+*/
+func TestColorAtMiss(t *testing.T) {
+	w := defaultWorld()
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 1, 0)}
+	c := w.ColorAt(r)
+	assert.True(t, c.Equal(Black))
+}
+
+/*
+	Scenario: The color when a ray hits
+	Given w ← default_world()
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	When c ← color_at(w, r)
+	Then c = color(0.38066, 0.47583, 0.2855)
+*/
+func TestColorAtHit(t *testing.T) {
+	w := defaultWorld()
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	c := w.ColorAt(r)
+	assert.True(t, c.Equal(Color{0.38066, 0.47583, 0.2855}))
+}
+
+/*
+	Scenario: The color with an intersection behind the ray
+	Given w ← default_world()
+	And outer ← the first object in w
+	And outer.material.ambient ← 1
+	And inner ← the second object in w
+	And inner.material.ambient ← 1
+	And r ← ray(point(0, 0, 0.75), vector(0, 0, -1))
+	When c ← color_at(w, r)
+	Then c = inner.material.color
+*/
+func TestColorAtIntersectionBehindRay(t *testing.T) {
+	w := defaultWorld()
+	w.Objects[0].Material().Ambient = 1
+	w.Objects[1].Material().Ambient = 1
+	inner := w.Objects[1]
+	r := Ray{NewPoint(0, 0, 0.75), NewVector(0, 0, -1)}
+	c := w.ColorAt(r)
+	assert.True(t, c.Equal(inner.Material().Color))
+}
+
+/*
+	Scenario: The nearest object wins regardless of order in the world
+	Given w ← world()
+	And w.light ← point_light(point(-10, 10, -10), color(1, 1, 1))
+	And near ← sphere() with:
+		| material.color     | (1, 0, 0)              |
+		| material.ambient   | 1                      |
+		| material.diffuse   | 0                      |
+		| material.specular  | 0                      |
+	And far ← sphere() with:
+		| transform          | translation(0, 0, 5)   |
+		| material.color     | (0, 0, 1)              |
+		| material.ambient   | 1                      |
+		| material.diffuse   | 0                      |
+		| material.specular  | 0                      |
+	And w contains far, then near
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	When c ← color_at(w, r)
+	Then c = color(1, 0, 0)
+*/
+func TestColorAtNearestObject(t *testing.T) {
+	m := NewMaterial()
+	m.Ambient = 1
+	m.Diffuse = 0
+	m.Specular = 0
+
+	m.Color = Red
+	near := NewSphere().WithMaterial(m)
+	m.Color = Blue
+	far := NewSphere().WithTransform(NewTranslation(0, 0, 5)).WithMaterial(m)
+
+	w := NewWorld()
+	w.Lights = []PointLight{{NewPoint(-10, 10, -10), Color{1, 1, 1}}}
+	w.Objects = []Shape{far, near}
+
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	c := w.ColorAt(r)
+	assert.True(t, c.Equal(Red))
+}
+
+/*
+	Scenario: There is no shadow when nothing is collinear with point and light
+	Given w ← default_world()
+	And p ← point(0, 10, 0)
+	Then is_shadowed(w, p) is false
+*/
+func TestNoShadowNothingCollinear(t *testing.T) {
+	w := defaultWorld()
+	p := NewPoint(0, 10, 0)
+	assert.False(t, w.IsShadowed(w.Lights[0], p))
+}
+
+/*
+	Scenario: The shadow when an object is between the point and the light
+	Given w ← default_world()
+	And p ← point(10, -10, 10)
+	Then is_shadowed(w, p) is true
+*/
+func TestShadowObjectBetweenPointAndLight(t *testing.T) {
+	w := defaultWorld()
+	p := NewPoint(10, -10, 10)
+	assert.True(t, w.IsShadowed(w.Lights[0], p))
+}
+
+/*
+	Scenario: There is no shadow when an object is behind the light
+	Given w ← default_world()
+	And p ← point(-20, 20, -20)
+	Then is_shadowed(w, p) is false
+*/
+func TestNoShadowObjectBehindLight(t *testing.T) {
+	w := defaultWorld()
+	p := NewPoint(-20, 20, -20)
+	assert.False(t, w.IsShadowed(w.Lights[0], p))
+}
+
+/*
+	Scenario: There is no shadow when an object is behind the point
+	Given w ← default_world()
+	And p ← point(-2, 2, -2)
+	Then is_shadowed(w, p) is false
+*/
+func TestNoShadowObjectBehindPoint(t *testing.T) {
+	w := defaultWorld()
+	p := NewPoint(-2, 2, -2)
+	assert.False(t, w.IsShadowed(w.Lights[0], p))
+}
+
+/*
+	Scenario: shade_hit() is given an intersection in shadow
+	Given w ← world()
+	And w.light ← point_light(point(0, 0, -10), color(1, 1, 1))
+	And s1 ← sphere()
+	And s1 is added to w
+	And s2 ← sphere() with:
+		| transform | translation(0, 0, 10) |
+	And s2 is added to w
+	And r ← ray(point(0, 0, 5), vector(0, 0, 1))
+	And i ← intersection(4, s2)
+	When comps ← prepare_computations(i, r)
+	And c ← shade_hit(w, comps)
+	Then c = color(0.1, 0.1, 0.1)
+*/
+func TestShadeHitInShadow(t *testing.T) {
+	w := NewWorld()
+	w.Lights = []PointLight{{NewPoint(0, 0, -10), Color{1, 1, 1}}}
+	s1 := NewSphere()
+	s2 := NewSphere().WithTransform(NewTranslation(0, 0, 10))
+	w.Objects = []Shape{s1, s2}
+	r := Ray{NewPoint(0, 0, 5), NewVector(0, 0, 1)}
+	i := NewIntersection(4, s2)
+	comps := PrepareComputations(i, r, Intersections{i})
+	c := w.ShadeHit(comps, DefaultMaxDepth)
+	assert.True(t, c.Equal(Color{0.1, 0.1, 0.1}))
+}
+
+/*
+	Scenario: Each light casts its own shadow
+	Given w ← world()
+	And w has lights at point(-10, 0, -10) and point(10, 0, -10)
+	And s ← sphere()
+	And blocker ← sphere() with:
+		| transform | scaling(0.5, 0.5, 0.5) then translation(-5, 0, -5.5) |
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And i ← intersection(4, s)
+	When comps ← prepare_computations(i, r)
+	And c ← shade_hit(w, comps)
+	Then c = ambient from the first light plus all of the second light
+*/
+func TestShadeHitShadowPerLight(t *testing.T) {
+	w := NewWorld()
+	left := PointLight{NewPoint(-10, 0, -10), Color{1, 1, 1}}
+	right := PointLight{NewPoint(10, 0, -10), Color{1, 1, 1}}
+	w.Lights = []PointLight{left, right}
+	s := NewSphere()
+	// halfway between the left light and the point the ray strikes
+	blocker := NewSphere().WithTransform(NewScaling(0.5, 0.5, 0.5).Translate(-5, 0, -5.5))
+	w.Objects = []Shape{s, blocker}
+
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	i := NewIntersection(4, s)
+	comps := PrepareComputations(i, r, Intersections{i})
+	c := w.ShadeHit(comps, DefaultMaxDepth)
+
+	m := s.Material()
+	litLeft := m.Lighting(s, left, comps.OverPoint, comps.EyeV, comps.NormalV, false)
+	shadowedLeft := m.Lighting(s, left, comps.OverPoint, comps.EyeV, comps.NormalV, true)
+	litRight := m.Lighting(s, right, comps.OverPoint, comps.EyeV, comps.NormalV, false)
+
+	// unblocked, the left light would add diffuse on top of its ambient
+	require.True(t, litLeft.Red > shadowedLeft.Red+0.1)
+	assert.True(t, w.IsShadowed(left, comps.OverPoint))
+	assert.False(t, w.IsShadowed(right, comps.OverPoint))
+	assert.True(t, c.Equal(shadowedLeft.Add(litRight)), "%v", c)
+}
+
+/*
+	Scenario: The reflected color for a nonreflective material
+	Given w ← default_world()
+	And r ← ray(point(0, 0, 0), vector(0, 0, 1))
+	And shape ← the second object in w
+	And shape.material.ambient ← 1
+	And i ← intersection(1, shape)
+	When comps ← prepare_computations(i, r)
+	And color ← reflected_color(w, comps)
+	Then color = color(0, 0, 0)
+*/
+func TestReflectedColorNonreflective(t *testing.T) {
+	w := defaultWorld()
+	r := Ray{NewPoint(0, 0, 0), NewVector(0, 0, 1)}
+	shape := w.Objects[1]
+	shape.Material().Ambient = 1
+	i := NewIntersection(1, shape)
+	comps := PrepareComputations(i, r, Intersections{i})
+	assert.Equal(t, Black, w.ReflectedColor(comps, DefaultMaxDepth))
+}
+
+// reflectiveFloor - default world plus a half reflective plane at y = -1,
+// and the ray that strikes it at 45° from behind the spheres
+func reflectiveFloor() (World, Shape, Ray) {
+	w := defaultWorld()
+	shape := NewPlane().WithTransform(NewTranslation(0, -1, 0))
+	shape.Material().Reflective = 0.5
+	w.Objects = append(w.Objects, shape)
+	r := Ray{NewPoint(0, 0, -3), NewVector(0, -math.Sqrt2/2, math.Sqrt2/2)}
+	return w, shape, r
+}
+
+/*
+	Scenario: The reflected color for a reflective material
+	Given w ← default_world()
+	And shape ← plane() with:
+		| material.reflective | 0.5                   |
+		| transform           | translation(0, -1, 0) |
+	And shape is added to w
+	And r ← ray(point(0, 0, -3), vector(0, -√2/2, √2/2))
+	And i ← intersection(√2, shape)
+	When comps ← prepare_computations(i, r)
+	And color ← reflected_color(w, comps)
+	Then color = color(0.19032, 0.2379, 0.14274)
+*/
+func TestReflectedColorReflective(t *testing.T) {
+	w, shape, r := reflectiveFloor()
+	i := NewIntersection(math.Sqrt2, shape)
+	comps := PrepareComputations(i, r, Intersections{i})
+	c := w.ReflectedColor(comps, DefaultMaxDepth)
+	assert.InDeltaSlice(t, []float64{0.19032, 0.2379, 0.14274}, []float64{c.Red, c.Green, c.Blue}, 0.0001)
+}
+
+/*
+	Scenario: shade_hit() with a reflective material
+	Given w ← default_world()
+	And shape ← plane() with:
+		| material.reflective | 0.5                   |
+		| transform           | translation(0, -1, 0) |
+	And shape is added to w
+	And r ← ray(point(0, 0, -3), vector(0, -√2/2, √2/2))
+	And i ← intersection(√2, shape)
+	When comps ← prepare_computations(i, r)
+	And color ← shade_hit(w, comps)
+	Then color = color(0.87677, 0.92436, 0.82918)
+*/
+func TestShadeHitReflective(t *testing.T) {
+	w, shape, r := reflectiveFloor()
+	i := NewIntersection(math.Sqrt2, shape)
+	comps := PrepareComputations(i, r, Intersections{i})
+	c := w.ShadeHit(comps, DefaultMaxDepth)
+	assert.InDeltaSlice(t, []float64{0.87677, 0.92436, 0.82918}, []float64{c.Red, c.Green, c.Blue}, 0.0001)
+}
+
+/*
+	Scenario: color_at() with mutually reflective surfaces
+	Given w ← world()
+	And w.light ← point_light(point(0, 0, 0), color(1, 1, 1))
+	And lower ← plane() with:
+		| material.reflective | 1                     |
+		| transform           | translation(0, -1, 0) |
+	And lower is added to w
+	And upper ← plane() with:
+		| material.reflective | 1                    |
+		| transform           | translation(0, 1, 0) |
+	And upper is added to w
+	And r ← ray(point(0, 0, 0), vector(0, 1, 0))
+	Then color_at(w, r) should terminate successfully
+*/
+func TestColorAtMutuallyReflective(t *testing.T) {
+	w := NewWorld()
+	w.Lights = []PointLight{{NewPoint(0, 0, 0), Color{1, 1, 1}}}
+	lower := NewPlane().WithTransform(NewTranslation(0, -1, 0))
+	lower.Material().Reflective = 1
+	upper := NewPlane().WithTransform(NewTranslation(0, 1, 0))
+	upper.Material().Reflective = 1
+	w.Objects = []Shape{lower, upper}
+	r := Ray{NewPoint(0, 0, 0), NewVector(0, 1, 0)}
+
+	// the light sits between the mirrors, each bounce adds the same
+	// ambient and diffuse again, one for the camera ray plus one per bounce
+	c := w.ColorAt(r)
+	i := NewIntersection(1, upper)
+	comps := PrepareComputations(i, r, Intersections{i})
+	single := w.ShadeHit(comps, 0)
+	assert.True(t, c.Equal(single.MulS(DefaultMaxDepth+1)), "%v", c)
+}
+
+/*
+	Scenario: The reflected color at the maximum recursive depth
+	Given w ← default_world()
+	And shape ← plane() with:
+		| material.reflective | 0.5                   |
+		| transform           | translation(0, -1, 0) |
+	And shape is added to w
+	And r ← ray(point(0, 0, -3), vector(0, -√2/2, √2/2))
+	And i ← intersection(√2, shape)
+	When comps ← prepare_computations(i, r)
+	And color ← reflected_color(w, comps, 0)
+	Then color = color(0, 0, 0)
+*/
+func TestReflectedColorMaxDepth(t *testing.T) {
+	w, shape, r := reflectiveFloor()
+	i := NewIntersection(math.Sqrt2, shape)
+	comps := PrepareComputations(i, r, Intersections{i})
+	assert.Equal(t, Black, w.ReflectedColor(comps, 0))
+}
+
+/*
+	Scenario: A world with a max depth of 0 has no reflections
+	Given w ← default_world() with a reflective floor
+	And w.max_depth ← 0
+	And r ← ray(point(0, 0, -3), vector(0, -√2/2, √2/2))
+	When c ← color_at(w, r)
+	Then c = shade_hit of the floor without its reflective term
+*/
+func TestColorAtMaxDepthZero(t *testing.T) {
+	w, shape, r := reflectiveFloor()
+	w.MaxDepth = 0
+	c := w.ColorAt(r)
+	shape.Material().Reflective = 0
+	i := NewIntersection(math.Sqrt2, shape)
+	comps := PrepareComputations(i, r, Intersections{i})
+	assert.True(t, c.Equal(w.ShadeHit(comps, DefaultMaxDepth)))
+}
+
+/*
+	Scenario: The refracted color with an opaque surface
+	Given w ← default_world()
+	And shape ← the first object in w
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And xs ← intersections(4:shape, 6:shape)
+	When comps ← prepare_computations(xs[0], r, xs)
+	And c ← refracted_color(w, comps, 5)
+	Then c = color(0, 0, 0)
+*/
+func TestRefractedColorOpaque(t *testing.T) {
+	w := defaultWorld()
+	shape := w.Objects[0]
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	xs := Intersections{NewIntersection(4, shape), NewIntersection(6, shape)}
+	comps := PrepareComputations(xs[0], r, xs)
+	assert.Equal(t, Black, w.RefractedColor(comps, 5))
+}
+
+/*
+	Scenario: The refracted color at the maximum recursive depth
+	Given w ← default_world()
+	And shape ← the first object in w
+	And shape has:
+		| material.transparency     | 1.0 |
+		| material.refractive_index | 1.5 |
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	And xs ← intersections(4:shape, 6:shape)
+	When comps ← prepare_computations(xs[0], r, xs)
+	And c ← refracted_color(w, comps, 0)
+	Then c = color(0, 0, 0)
+*/
+func TestRefractedColorMaxDepth(t *testing.T) {
+	w := defaultWorld()
+	shape := w.Objects[0]
+	shape.Material().Transparency = 1
+	shape.Material().RefractiveIndex = 1.5
+	r := Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}
+	xs := Intersections{NewIntersection(4, shape), NewIntersection(6, shape)}
+	comps := PrepareComputations(xs[0], r, xs)
+	assert.Equal(t, Black, w.RefractedColor(comps, 0))
+}
+
+/*
+	Scenario: The refracted color under total internal reflection
+	Given w ← default_world()
+	And shape ← the first object in w
+	And shape has:
+		| material.transparency     | 1.0 |
+		| material.refractive_index | 1.5 |
+	And r ← ray(point(0, 0, √2/2), vector(0, 1, 0))
+	And xs ← intersections(-√2/2:shape, √2/2:shape)
+	# NOTE: this time you're inside the sphere, so you need
+	# to look at the second intersection, xs[1], not xs[0]
+	When comps ← prepare_computations(xs[1], r, xs)
+	And c ← refracted_color(w, comps, 5)
+	Then c = color(0, 0, 0)
+*/
+func TestRefractedColorTotalInternalReflection(t *testing.T) {
+	w := defaultWorld()
+	shape := w.Objects[0]
+	shape.Material().Transparency = 1
+	shape.Material().RefractiveIndex = 1.5
+	r := Ray{NewPoint(0, 0, math.Sqrt2/2), NewVector(0, 1, 0)}
+	xs := Intersections{NewIntersection(-math.Sqrt2/2, shape), NewIntersection(math.Sqrt2/2, shape)}
+	comps := PrepareComputations(xs[1], r, xs)
+	assert.Equal(t, Black, w.RefractedColor(comps, 5))
+}
+
+/*
+	Scenario: The refracted color with a refracted ray
+	Given w ← default_world()
+	And A ← the first object in w
+	And A has:
+		| material.ambient | 1.0            |
+		| material.pattern | test_pattern() |
+	And B ← the second object in w
+	And B has:
+		| material.transparency     | 1.0 |
+		| material.refractive_index | 1.5 |
+	And r ← ray(point(0, 0, 0.1), vector(0, 1, 0))
+	And xs ← intersections(-0.9899:A, -0.4899:B, 0.4899:B, 0.9899:A)
+	When comps ← prepare_computations(xs[2], r, xs)
+	And c ← refracted_color(w, comps, 5)
+	Then c = color(0, 0.99888, 0.04725)
+*/
+func TestRefractedColorRefractedRay(t *testing.T) {
+	w := defaultWorld()
+	a := w.Objects[0]
+	a.Material().Ambient = 1
+	a.Material().Pattern = newTestPattern()
+	b := w.Objects[1]
+	b.Material().Transparency = 1
+	b.Material().RefractiveIndex = 1.5
+	r := Ray{NewPoint(0, 0, 0.1), NewVector(0, 1, 0)}
+	xs := Intersections{
+		NewIntersection(-0.9899, a),
+		NewIntersection(-0.4899, b),
+		NewIntersection(0.4899, b),
+		NewIntersection(0.9899, a),
+	}
+	comps := PrepareComputations(xs[2], r, xs)
+	c := w.RefractedColor(comps, 5)
+	assert.InDeltaSlice(t, []float64{0, 0.99888, 0.04725}, []float64{c.Red, c.Green, c.Blue}, 0.0001)
+}
+
+// transparentFloor - default world plus a half transparent floor at
+// y = -1 over a red ball, and the ray that strikes the floor at 45°
+func transparentFloor() (World, Shape, Ray) {
+	w := defaultWorld()
+	floor := NewPlane().WithTransform(NewTranslation(0, -1, 0))
+	floor.Material().Transparency = 0.5
+	floor.Material().RefractiveIndex = 1.5
+	ball := NewSphere().WithTransform(NewTranslation(0, -3.5, -0.5))
+	ball.Material().Color = Color{1, 0, 0}
+	ball.Material().Ambient = 0.5
+	w.Objects = append(w.Objects, floor, ball)
+	r := Ray{NewPoint(0, 0, -3), NewVector(0, -math.Sqrt2/2, math.Sqrt2/2)}
+	return w, floor, r
+}
+
+/*
+	Scenario: shade_hit() with a transparent material
+	Given w ← default_world()
+	And floor ← plane() with:
+		| transform                 | translation(0, -1, 0) |
+		| material.transparency     | 0.5                   |
+		| material.refractive_index | 1.5                   |
+	And floor is added to w
+	And ball ← sphere() with:
+		| material.color     | (1, 0, 0)                  |
+		| material.ambient   | 0.5                        |
+		| transform          | translation(0, -3.5, -0.5) |
+	And ball is added to w
+	And r ← ray(point(0, 0, -3), vector(0, -√2/2, √2/2))
+	And xs ← intersections(√2:floor)
+	When comps ← prepare_computations(xs[0], r, xs)
+	And color ← shade_hit(w, comps, 5)
+	Then color = color(0.93642, 0.68642, 0.68642)
+*/
+func TestShadeHitTransparent(t *testing.T) {
+	w, floor, r := transparentFloor()
+	xs := Intersections{NewIntersection(math.Sqrt2, floor)}
+	comps := PrepareComputations(xs[0], r, xs)
+	c := w.ShadeHit(comps, 5)
+	assert.InDeltaSlice(t, []float64{0.93642, 0.68642, 0.68642}, []float64{c.Red, c.Green, c.Blue}, 0.0001)
+}
+
+/*
+	Scenario: shade_hit() with a reflective, transparent material
+	Given w ← default_world()
+	And r ← ray(point(0, 0, -3), vector(0, -√2/2, √2/2))
+	And floor ← plane() with:
+		| transform                 | translation(0, -1, 0) |
+		| material.reflective       | 0.5                   |
+		| material.transparency     | 0.5                   |
+		| material.refractive_index | 1.5                   |
+	And floor is added to w
+	And ball ← sphere() with:
+		| material.color     | (1, 0, 0)                  |
+		| material.ambient   | 0.5                        |
+		| transform          | translation(0, -3.5, -0.5) |
+	And ball is added to w
+	And xs ← intersections(√2:floor)
+	When comps ← prepare_computations(xs[0], r, xs)
+	And color ← shade_hit(w, comps, 5)
+	Then color = color(0.93391, 0.69643, 0.69243)
+*/
+func TestShadeHitReflectiveTransparent(t *testing.T) {
+	w, floor, r := transparentFloor()
+	floor.Material().Reflective = 0.5
+	xs := Intersections{NewIntersection(math.Sqrt2, floor)}
+	comps := PrepareComputations(xs[0], r, xs)
+	c := w.ShadeHit(comps, 5)
+	assert.InDeltaSlice(t, []float64{0.93391, 0.69643, 0.69243}, []float64{c.Red, c.Green, c.Blue}, 0.0001)
+}
+
+/*
+	Scenario: The Schlick approximation under total internal reflection
+	Given shape ← glass_sphere()
+	And r ← ray(point(0, 0, √2/2), vector(0, 1, 0))
+	And xs ← intersections(-√2/2:shape, √2/2:shape)
+	When comps ← prepare_computations(xs[1], r, xs)
+	And reflectance ← schlick(comps)
+	Then reflectance = 1.0
+
+	Scenario: The Schlick approximation with a perpendicular viewing angle
+	Given shape ← glass_sphere()
+	And r ← ray(point(0, 0, 0), vector(0, 1, 0))
+	And xs ← intersections(-1:shape, 1:shape)
+	When comps ← prepare_computations(xs[1], r, xs)
+	And reflectance ← schlick(comps)
+	Then reflectance = 0.04
+
+	Scenario: The Schlick approximation with small angle and n2 > n1
+	Given shape ← glass_sphere()
+	And r ← ray(point(0, 0.99, -2), vector(0, 0, 1))
+	And xs ← intersections(1.8589:shape)
+	When comps ← prepare_computations(xs[0], r, xs)
+	And reflectance ← schlick(comps)
+	Then reflectance = 0.48873
+*/
+func TestSchlick(t *testing.T) {
+	shape := NewGlassSphere()
+	examples := []struct {
+		r           Ray
+		xs          Intersections
+		index       int
+		reflectance float64
+	}{
+		{
+			Ray{NewPoint(0, 0, math.Sqrt2/2), NewVector(0, 1, 0)},
+			Intersections{NewIntersection(-math.Sqrt2/2, shape), NewIntersection(math.Sqrt2/2, shape)},
+			1, 1,
+		},
+		{
+			Ray{NewPoint(0, 0, 0), NewVector(0, 1, 0)},
+			Intersections{NewIntersection(-1, shape), NewIntersection(1, shape)},
+			1, 0.04,
+		},
+		{
+			Ray{NewPoint(0, 0.99, -2), NewVector(0, 0, 1)},
+			Intersections{NewIntersection(1.8589, shape)},
+			0, 0.48873,
+		},
+	}
+	for _, e := range examples {
+		comps := PrepareComputations(e.xs[e.index], e.r, e.xs)
+		assert.InDelta(t, e.reflectance, Schlick(comps), 0.0001)
+	}
+}
+
+/*
+	Scenario: color_at() sees through a glass sphere to what is behind it
+	Given w ← world() with a light at point(-10, 10, -10)
+	And glass ← glass_sphere()
+	And wall ← plane() with:
+		| transform      | translation(0, 0, 5) * rotation_x(π/2) |
+		| material.color | (1, 0, 0)                              |
+	And r ← ray(point(0, 0, -5), vector(0, 0, 1))
+	When c ← color_at(w, r)
+	Then c is red
+	And with w.max_depth ← 0, color_at(w, r) = color(0, 0, 0)
+*/
+func TestColorAtThroughGlass(t *testing.T) {
+	w := NewWorld()
+	w.Lights = []PointLight{{NewPoint(-10, 10, -10), Color{1, 1, 1}}}
+	glass := NewGlassSphere()
+	glass.Material().Diffuse = 0
+	glass.Material().Ambient = 0
+	wall := NewPlane().WithTransform(NewRotationX(math.Pi/2).Translate(0, 0, 5))
+	wall.Material().Color = Color{1, 0, 0}
+	w.Objects = []Shape{glass, wall}
+	// the glass itself only adds a specular highlight off to the side, the
+	// color comes from the wall seen through it
+	c := w.ColorAt(Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)})
+	assert.True(t, c.Red > 0.05, "%v", c)
+	assert.InDelta(t, 0, c.Green, 0.00001)
+	assert.InDelta(t, 0, c.Blue, 0.00001)
+
+	// with refraction off the glass is black
+	w.MaxDepth = 0
+	assert.True(t, w.ColorAt(Ray{NewPoint(0, 0, -5), NewVector(0, 0, 1)}).Equal(Black))
+}